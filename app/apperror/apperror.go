@@ -0,0 +1,140 @@
+// Package apperror berisi error domain bertipe yang dikembalikan repository dan service.
+// Error handler aplikasi memetakannya ke status HTTP dan envelope respons yang seragam.
+// Error tidak membawa pesan; pesan disusun dari katalog i18n berdasarkan Code dan Params.
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type Kind string
+
+const (
+	KindBadRequest   Kind = "bad_request"
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindInternal     Kind = "internal"
+)
+
+// Kode error generik, dipakai jika tidak ada kode yang lebih spesifik
+const (
+	CodeBadRequest   = "BAD_REQUEST"
+	CodeValidation   = "VALIDATION_FAILED"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeForbidden    = "FORBIDDEN"
+	CodeNotFound     = "NOT_FOUND"
+	CodeConflict     = "CONFLICT"
+	CodeInternal     = "INTERNAL_ERROR"
+)
+
+// FieldError menjelaskan kesalahan pada satu field input.
+// Message diisi dalam bahasa default dan diterjemahkan ulang oleh error handler sesuai bahasa request.
+type FieldError struct {
+	Field   string                 `json:"field"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+type Error struct {
+	Kind   Kind
+	Code   string                 // kode mesin sekaligus kunci katalog pesan, contoh USER_NOT_FOUND
+	Fields []FieldError           // khusus KindValidation
+	Params map[string]interface{} // nilai placeholder pada pesan, ikut dikirim ke client
+	Err    error                  // penyebab asli, hanya untuk log dan tidak pernah dikirim ke client
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Code, e.Err)
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is membuat errors.Is(err, apperror.ErrNotFound) bernilai true untuk seluruh error berjenis not found
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Kind == e.Kind && (t.Code == "" || t.Code == e.Code)
+}
+
+// With menambahkan parameter yang dapat dipakai saat menyusun pesan
+func (e *Error) With(key string, value interface{}) *Error {
+	if e.Params == nil {
+		e.Params = map[string]interface{}{}
+	}
+	e.Params[key] = value
+	return e
+}
+
+// Sentinel per jenis untuk dipakai bersama errors.Is
+var (
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrValidation   = &Error{Kind: KindValidation}
+)
+
+func New(kind Kind, code string) *Error {
+	return &Error{Kind: kind, Code: code}
+}
+
+func BadRequest(code string) *Error {
+	return New(KindBadRequest, code)
+}
+
+func Unauthorized(code string) *Error {
+	return New(KindUnauthorized, code)
+}
+
+func Forbidden(code string) *Error {
+	return New(KindForbidden, code)
+}
+
+func NotFound(code string) *Error {
+	return New(KindNotFound, code)
+}
+
+func Conflict(code string) *Error {
+	return New(KindConflict, code)
+}
+
+// Validation membuat error validasi dengan daftar field yang salah
+func Validation(fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidation, Fields: fields}
+}
+
+// Internal membungkus error tak terduga; pesan asli hanya dicatat di log
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: CodeInternal, Err: err}
+}
+
+// Wrap menambahkan penyebab asli ke error domain
+func Wrap(err error, e *Error) *Error {
+	e.Err = err
+	return e
+}
+
+// IsNotFound juga mengenali sql.ErrNoRows agar repository lama tetap terpetakan dengan benar
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows)
+}
+
+// As mengambil *Error dari rantai error
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
@@ -0,0 +1,75 @@
+package apperror
+
+// Kode error domain. Kode bersifat stabil dan dipakai client untuk menangani error secara spesifik,
+// sedangkan pesan boleh berubah sewaktu-waktu.
+const (
+	// Request
+	CodeInvalidID        = "INVALID_ID"
+	CodeInvalidBody      = "INVALID_BODY"
+	CodeInvalidQuery     = "INVALID_QUERY"
+	CodeInvalidInput     = "INVALID_INPUT"
+	CodeRouteNotFound    = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	CodeDuplicateValue   = "DUPLICATE_VALUE"
+	CodeReferenceInvalid = "REFERENCE_VIOLATION"
+
+	// Autentikasi & otorisasi
+	CodeTokenRequired          = "TOKEN_REQUIRED"
+	CodeTokenMalformed         = "TOKEN_MALFORMED"
+	CodeTokenInvalid           = "TOKEN_INVALID"
+	CodeRefreshTokenInvalid    = "REFRESH_TOKEN_INVALID"
+	CodeSessionRevoked         = "SESSION_REVOKED"
+	CodeSessionNotBound        = "SESSION_NOT_BOUND"
+	CodeSessionNotFound        = "SESSION_NOT_FOUND"
+	CodeInvalidCredentials     = "INVALID_CREDENTIALS"
+	CodeAccountInactive        = "ACCOUNT_INACTIVE"
+	CodeImpersonationForbidden = "IMPERSONATION_FORBIDDEN"
+	CodePermissionDenied       = "PERMISSION_DENIED"
+	CodeScopeDenied            = "SCOPE_DENIED"
+
+	// User
+	CodeUserNotFound        = "USER_NOT_FOUND"
+	CodeUserAlreadyExists   = "USER_ALREADY_EXISTS"
+	CodeLastAdminPermission = "LAST_ADMIN_PERMISSION"
+	CodeImpersonateSelf     = "IMPERSONATE_SELF"
+	CodeImpersonateInactive = "IMPERSONATE_INACTIVE"
+	CodeImpersonateAdmin    = "IMPERSONATE_ADMIN"
+
+	CodeUserDeleted         = "USER_DELETED"
+	CodeUserNotDeleted      = "USER_NOT_DELETED"
+	CodeUserAnonymized      = "USER_ANONYMIZED"
+	CodeUserHasDependencies = "USER_HAS_DEPENDENCIES"
+
+	// Import user
+	CodeImportFileRequired      = "IMPORT_FILE_REQUIRED"
+	CodeImportFormatUnsupported = "IMPORT_FORMAT_UNSUPPORTED"
+	CodeImportFileInvalid       = "IMPORT_FILE_INVALID"
+	CodeImportTooManyRows       = "IMPORT_TOO_MANY_ROWS"
+
+	// Role & permission
+	CodeRoleNotFound           = "ROLE_NOT_FOUND"
+	CodeRoleNameTaken          = "ROLE_NAME_TAKEN"
+	CodeSystemRoleProtected    = "SYSTEM_ROLE_PROTECTED"
+	CodeRoleInUse              = "ROLE_IN_USE"
+	CodePermissionNotFound     = "PERMISSION_NOT_FOUND"
+	CodeRolePermissionNotFound = "ROLE_PERMISSION_NOT_FOUND"
+
+	// Mahasiswa & dosen
+	CodeStudentNotFound  = "STUDENT_NOT_FOUND"
+	CodeLecturerNotFound = "LECTURER_NOT_FOUND"
+	CodeAdvisorNotFound  = "ADVISOR_NOT_FOUND"
+
+	CodeLecturerNIPTaken      = "LECTURER_NIP_TAKEN"
+	CodeLecturerProfileExists = "LECTURER_PROFILE_EXISTS"
+	CodeUserNotLecturer       = "USER_NOT_LECTURER"
+	CodeReassignSameLecturer  = "REASSIGN_SAME_LECTURER"
+
+	CodeStudentNIMTaken         = "STUDENT_NIM_TAKEN"
+	CodeStudentStatusTransition = "STUDENT_STATUS_TRANSITION_INVALID"
+	CodeStudentNotActive        = "STUDENT_NOT_ACTIVE"
+
+	// Prestasi
+	CodeAchievementNotFound     = "ACHIEVEMENT_NOT_FOUND"
+	CodeAchievementNotDraft     = "ACHIEVEMENT_NOT_DRAFT"
+	CodeAchievementNotSubmitted = "ACHIEVEMENT_NOT_SUBMITTED"
+)
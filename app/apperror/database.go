@@ -0,0 +1,70 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Kode SQLSTATE PostgreSQL yang dipetakan ke error domain
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqInvalidText         = "22P02"
+)
+
+// IsUniqueViolation mengecek pelanggaran constraint unique PostgreSQL
+func IsUniqueViolation(err error) bool {
+	return pqCode(err) == pqUniqueViolation
+}
+
+// IsForeignKeyViolation mengecek pelanggaran foreign key PostgreSQL
+func IsForeignKeyViolation(err error) bool {
+	return pqCode(err) == pqForeignKeyViolation
+}
+
+// ViolatedConstraint mengembalikan nama constraint/index yang dilanggar, atau "" jika bukan error constraint
+func ViolatedConstraint(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
+
+// FromDatabase memetakan error driver database yang umum ke error domain generik.
+// Error lain (termasuk *Error) dikembalikan apa adanya.
+func FromDatabase(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := As(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, mongo.ErrNoDocuments):
+		return Wrap(err, NotFound(CodeNotFound))
+	}
+
+	switch pqCode(err) {
+	case pqUniqueViolation:
+		return Wrap(err, Conflict(CodeDuplicateValue))
+	case pqForeignKeyViolation:
+		return Wrap(err, Conflict(CodeReferenceInvalid))
+	case pqInvalidText:
+		return Wrap(err, BadRequest(CodeInvalidInput))
+	}
+
+	return err
+}
+
+func pqCode(err error) pq.ErrorCode {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code
+	}
+	return ""
+}
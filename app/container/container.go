@@ -0,0 +1,103 @@
+package container
+
+import (
+	"database/sql"
+	"log/slog"
+	"sync"
+	"uas/app/repository"
+	"uas/app/services"
+	"uas/config"
+	"uas/middleware"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Application memegang koneksi database, repository dan service yang dipakai bersama
+// oleh seluruh route, sehingga setiap request memakai satu pool yang sama.
+type Application struct {
+	Config       *config.Config
+	Postgres     *sql.DB
+	Mongo        *mongo.Database
+	Repositories Repositories
+	Services     Services
+	Authorizer   *middleware.Authorizer
+	Logger       *slog.Logger
+
+	workers sync.WaitGroup
+}
+
+type Repositories struct {
+	User         repository.UserRepository
+	Role         repository.RoleRepository
+	Session      repository.SessionRepository
+	Audit        repository.AuditRepository
+	Policy       repository.PolicyRepository
+	Student      repository.StudentRepository
+	Lecturer     repository.LecturerRepository
+	Achievement  repository.AchievementRepository
+	System       repository.SystemRepository
+	PersonalData repository.PersonalDataRepository
+}
+
+type Services struct {
+	Auth         services.AuthService
+	User         services.UserService
+	UserImport   services.UserImportService
+	Role         services.RoleService
+	Student      services.StudentService
+	Lecturer     services.LecturerService
+	Achievement  services.AchievementService
+	Audit        services.AuditService
+	System       services.SystemService
+	PersonalData services.PersonalDataService
+}
+
+// New menyusun Application dari koneksi database yang sudah terbuka
+func New(cfg *config.Config, pg *sql.DB, mongoDB *mongo.Database) *Application {
+	app := Build(cfg, NewRepositories(pg, mongoDB))
+	app.Postgres = pg
+	app.Mongo = mongoDB
+	return app
+}
+
+// Build menyusun Application dari repository yang sudah ada.
+// Dipakai juga oleh test untuk menyuntikkan repository palsu.
+func Build(cfg *config.Config, repos Repositories) *Application {
+	return &Application{
+		Config:       cfg,
+		Repositories: repos,
+		Services:     NewServices(cfg, repos),
+		Authorizer:   middleware.NewAuthorizer(repos.Policy),
+		Logger:       slog.Default(),
+	}
+}
+
+func NewRepositories(pg *sql.DB, mongoDB *mongo.Database) Repositories {
+	return Repositories{
+		User:         repository.NewUserRepository(pg, mongoDB),
+		Role:         repository.NewRoleRepository(pg),
+		Session:      repository.NewSessionRepository(pg),
+		Audit:        repository.NewAuditRepository(pg),
+		Policy:       repository.NewPolicyRepository(pg),
+		Student:      repository.NewStudentRepository(pg),
+		Lecturer:     repository.NewLecturerRepository(pg),
+		Achievement:  repository.NewAchievementRepository(pg, mongoDB),
+		System:       repository.NewSystemRepository(pg, mongoDB),
+		PersonalData: repository.NewPersonalDataRepository(pg, mongoDB),
+	}
+}
+
+func NewServices(cfg *config.Config, repos Repositories) Services {
+	return Services{
+		Auth:         services.NewAuthService(repos.User, repos.Session, repos.Audit, cfg.JWT),
+		User:         services.NewUserService(repos.User, repos.Role, repos.Session, repos.Audit),
+		UserImport:   services.NewUserImportService(repos.User, repos.Role, repos.Lecturer, repos.Audit),
+		Role:         services.NewRoleService(repos.Role, repos.Audit),
+		Student:      services.NewStudentService(repos.Student, repos.Audit),
+		Lecturer:     services.NewLecturerService(repos.Lecturer, repos.User, repos.Audit),
+		Achievement:  services.NewAchievementService(repos.Achievement, repos.Audit),
+		Audit:        services.NewAuditService(repos.Audit),
+		System:       services.NewSystemService(repos.System, cfg),
+		PersonalData: services.NewPersonalDataService(repos.PersonalData, repos.Audit),
+	}
+}
@@ -0,0 +1,69 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Go menjalankan background worker yang harus berhenti ketika ctx dibatalkan.
+// Shutdown menunggu seluruh worker selesai sebelum menutup koneksi database.
+func (a *Application) Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		fn(ctx)
+		slog.Info("background worker berhenti", "worker", name)
+	}()
+}
+
+// Every menjalankan fn secara berkala sampai ctx dibatalkan
+func Every(interval time.Duration, fn func()) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn()
+			}
+		}
+	}
+}
+
+// Shutdown menunggu background worker lalu menutup pool PostgreSQL dan client MongoDB.
+// Worker harus sudah diberi sinyal berhenti (ctx-nya dibatalkan) sebelum Shutdown dipanggil.
+func (a *Application) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+
+	var errs []error
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("menunggu background worker: %w", ctx.Err()))
+	}
+
+	if a.Postgres != nil {
+		if err := a.Postgres.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("menutup PostgreSQL: %w", err))
+		}
+	}
+
+	if a.Mongo != nil {
+		if err := a.Mongo.Client().Disconnect(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("menutup MongoDB: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -6,10 +6,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Jenis prestasi yang dapat diajukan mahasiswa
+var AchievementTypes = []string{"academic", "competition", "organization", "publication", "certification", "other"}
+
 type CreateAchievementRequest struct {
-	AchievementType string                 `json:"achievementType" validate:"required"`
-	Title           string                 `json:"title" validate:"required"`
-	Description     string                 `json:"description"`
+	AchievementType string                 `json:"achievementType" validate:"required,achievement_type"`
+	Title           string                 `json:"title" validate:"required,notblank,max=200"`
+	Description     string                 `json:"description" validate:"max=5000"`
 	Details         map[string]interface{} `json:"details"`
 	Tags            []string               `json:"tags"`
 	EventDate       time.Time              `json:"eventDate"`
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLog struct {
+	ID                   int64           `json:"id"`
+	ActorID              string          `json:"actor_id"`
+	ActorUsername        string          `json:"actor_username"`
+	Action               string          `json:"action"`
+	TargetType           string          `json:"target_type"`
+	TargetID             string          `json:"target_id"`
+	Before               json.RawMessage `json:"before,omitempty"`
+	After                json.RawMessage `json:"after,omitempty"`
+	IP                   string          `json:"ip"`
+	UserAgent            string          `json:"user_agent"`
+	RequestID            string          `json:"request_id"`
+	ImpersonatorID       string          `json:"impersonator_id,omitempty"`
+	ImpersonatorUsername string          `json:"impersonator_username,omitempty"`
+	PrevHash             string          `json:"prev_hash"`
+	Hash                 string          `json:"hash"`
+	PIIHash              string          `json:"pii_hash,omitempty"`
+	CreatedAt            time.Time       `json:"created_at"`
+	RedactedAt           *time.Time      `json:"redacted_at,omitempty"`
+}
+
+type AuditFilter struct {
+	ActorID        string
+	ImpersonatorID string
+	Action         string
+	TargetType     string
+	TargetID       string
+	From           *time.Time
+	To             *time.Time
+	Page           int
+	Limit          int
+}
+
+type AuditChainStatus struct {
+	Valid    bool  `json:"valid"`
+	Checked  int   `json:"checked"`
+	Redacted int   `json:"redacted"`
+	BrokenAt int64 `json:"broken_at,omitempty"`
+}
+
+// AuditRedactedIDsKey adalah field pada after entry user.anonymize yang mencatat ID audit log yang diredaksi
+const AuditRedactedIDsKey = "redacted_audit_ids"
+
+// Daftar action audit log
+const (
+	AuditUserCreate           = "user.create"
+	AuditUserUpdate           = "user.update"
+	AuditUserDelete           = "user.delete"
+	AuditUserRoleUpdate       = "user.role_update"
+	AuditStudentAdvisorUpdate = "student.advisor_update"
+	AuditAchievementVerify    = "achievement.verify"
+	AuditAchievementReject    = "achievement.reject"
+	AuditRoleCreate           = "role.create"
+	AuditRoleUpdate           = "role.update"
+	AuditRoleDelete           = "role.delete"
+	AuditRolePermissionGrant  = "role.permission_grant"
+	AuditRolePermissionRevoke = "role.permission_revoke"
+	AuditUserImpersonate      = "user.impersonate"
+	AuditUserForceLogout      = "user.force_logout"
+	AuditUserImport           = "user.import"
+	AuditDataExport           = "data.export"
+	AuditLecturerCreate       = "lecturer.create"
+	AuditLecturerUpdate       = "lecturer.update"
+	AuditLecturerReassign     = "lecturer.advisees_reassign"
+	AuditStudentUpdate        = "student.update"
+	AuditStudentStatusUpdate  = "student.status_update"
+	AuditUserAnonymize        = "user.anonymize"
+	AuditUserRestore          = "user.restore"
+	AuditPersonalDataExport   = "user.personal_data_export"
+)
@@ -0,0 +1,66 @@
+package models
+
+import "uas/app/apperror"
+
+// Kolom file import user (baris pertama file), urutan bebas dan tidak membedakan huruf besar/kecil
+var (
+	ImportColumns         = []string{"username", "email", "password", "full_name", "role", "student_id", "program_study", "academy_year", "advisor_nip", "lecturer_id", "department"}
+	ImportRequiredColumns = []string{"username", "email", "full_name", "role"}
+)
+
+// Batas import per file dan jumlah baris per transaksi
+const (
+	MaxImportRows   = 2000
+	ImportBatchSize = 100
+)
+
+// Status per baris pada laporan import
+const (
+	ImportStatusValid   = "valid"
+	ImportStatusInvalid = "invalid"
+	ImportStatusCreated = "created"
+	ImportStatusFailed  = "failed"
+)
+
+// ImportUserRow adalah satu baris file import. Nama field pada error validasi mengikuti nama kolom.
+type ImportUserRow struct {
+	Line         int    `json:"-"`
+	Username     string `json:"username" validate:"required,username"`
+	Email        string `json:"email" validate:"required,email,max=100"`
+	Password     string `json:"password" validate:"omitempty,password,max=72"`
+	FullName     string `json:"full_name" validate:"required,notblank,max=100"`
+	Role         string `json:"role" validate:"required"`
+	StudentID    string `json:"student_id" validate:"omitempty,nim"`
+	ProgramStudy string `json:"program_study" validate:"max=100"`
+	AcademicYear string `json:"academy_year" validate:"omitempty,numeric,len=4"`
+	AdvisorNIP   string `json:"advisor_nip" validate:"omitempty,nip"`
+	LecturerID   string `json:"lecturer_id" validate:"omitempty,nip"`
+	Department   string `json:"department" validate:"max=100"`
+}
+
+// ImportUser adalah baris valid yang siap disimpan beserta profilnya
+type ImportUser struct {
+	Line    int
+	User    User
+	Student *Student
+	Lecture *Lecture
+}
+
+type ImportRowResult struct {
+	Line              int                   `json:"line"`
+	Username          string                `json:"username"`
+	Status            string                `json:"status"`
+	UserID            string                `json:"user_id,omitempty"`
+	GeneratedPassword string                `json:"generated_password,omitempty"`
+	Errors            []apperror.FieldError `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Invalid int               `json:"invalid"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
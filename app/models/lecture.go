@@ -9,8 +9,8 @@ import (
 type Lecture struct {
 	ID uuid.UUID `json:"id"`
 	UserID uuid.UUID `json:"user_id"`
-	LectureID string `json:"lecturer_id"`
-	Department string `json:"department"`
+	LectureID string `json:"lecturer_id" validate:"required,nip"`
+	Department string `json:"department" validate:"required,max=100"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -30,6 +30,24 @@ type GetLecture struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+// CreateLecturerRequest membuat profil dosen untuk user ber-role Dosen Wali yang belum memiliki profil
+type CreateLecturerRequest struct {
+	UserID     string `json:"user_id" validate:"required,uuid"`
+	LecturerID string `json:"lecturer_id" validate:"required,nip"`
+	Department string `json:"department" validate:"required,notblank,max=100"`
+}
+
+type UpdateLecturerRequest struct {
+	LecturerID string `json:"lecturer_id" validate:"required,nip"`
+	Department string `json:"department" validate:"required,notblank,max=100"`
+}
+
+// ReassignAdviseesRequest memindahkan mahasiswa bimbingan ke dosen lain; student_ids kosong berarti seluruhnya
+type ReassignAdviseesRequest struct {
+	ToLecturerID string   `json:"to_lecturer_id" validate:"required,uuid"`
+	StudentIDs   []string `json:"student_ids" validate:"omitempty,dive,uuid"`
+}
+
 type RejectAchievementRequest struct {
-	RejectionNote string `json:"rejection_note" validate:"required"`
+	RejectionNote string `json:"rejection_note" validate:"required,notblank,max=1000"`
 }
\ No newline at end of file
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Batas pagination untuk endpoint daftar
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// Arah urutan
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// Kolom yang boleh dipakai pada query ?sort=, kolom pertama adalah default
+var (
+	UserSortFields     = []string{"full_name", "username", "email", "role_name", "created_at"}
+	StudentSortFields  = []string{"full_name", "nim", "program_study", "academy_year", "created_at"}
+	LecturerSortFields = []string{"full_name", "nip", "department", "created_at"}
+)
+
+// ListParams adalah parameter pagination, sorting dan pencarian yang sama untuk seluruh endpoint daftar.
+// Jika Cursor diisi, dipakai keyset pagination dan Page diabaikan.
+type ListParams struct {
+	Page   int
+	Limit  int
+	Cursor *Cursor
+	Sort   string
+	Order  string
+	Search string
+}
+
+// Cursor menandai baris terakhir halaman sebelumnya: nilai kolom sort dan id-nya.
+// Sort dan Order ikut disimpan agar cursor tidak dipakai dengan urutan yang berbeda.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// ListMeta dikirim pada field "meta" respons daftar
+type ListMeta struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	Sort       string `json:"sort"`
+	Order      string `json:"order"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type UserFilter struct {
+	ListParams
+	RoleID         string
+	RoleName       string
+	IsActive       *bool
+	IncludeDeleted bool
+}
+
+type StudentFilter struct {
+	ListParams
+	ProgramStudy string
+	AcademicYear string
+	AdvisorID    string
+	Status       string
+	IsActive     *bool
+}
+
+type LecturerFilter struct {
+	ListParams
+	Department string
+	IsActive   *bool
+}
+
+// Encode mengubah cursor menjadi string opaque untuk query ?cursor=
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor membaca cursor hasil Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == "" {
+		return nil, errors.New("cursor tanpa id")
+	}
+	return &c, nil
+}
@@ -3,9 +3,10 @@ package models
 import "github.com/google/uuid"
 
 type Permission struct {
-	ID          uuid.UUID
-	Name        string
-	Resource    string
-	Action      string
-	Description string
-}
\ No newline at end of file
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Resource    string    `json:"resource"`
+	Action      string    `json:"action"`
+	Description string    `json:"description"`
+	Scope       string    `json:"scope,omitempty"`
+}
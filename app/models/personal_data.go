@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// PersonalDataFormatVersion dinaikkan setiap kali susunan file di dalam ZIP berubah
+const PersonalDataFormatVersion = 1
+
+// PersonalDataSection adalah satu file JSON di dalam ZIP data pribadi. Records selalu berupa array
+// agar client dapat membaca setiap file dengan cara yang sama.
+type PersonalDataSection struct {
+	Name        string
+	Description string
+	Records     []interface{}
+}
+
+// PersonalDataManifest ditulis sebagai manifest.json dan menjelaskan isi ZIP
+type PersonalDataManifest struct {
+	FormatVersion int                `json:"format_version"`
+	UserID        string             `json:"user_id"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+	RequestedBy   PersonalDataActor  `json:"requested_by"`
+	Files         []PersonalDataFile `json:"files"`
+	Excluded      []string           `json:"excluded"`
+}
+
+type PersonalDataActor struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Self     bool   `json:"self"`
+}
+
+type PersonalDataFile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Records     int    `json:"records"`
+	SHA256      string `json:"sha256"`
+}
+
+// Data yang sengaja tidak ikut diexport beserta alasannya
+var PersonalDataExcluded = []string{
+	"users.password_hash: hash password bukan data yang dapat dibaca dan berisiko jika bocor",
+	"audit_logs.prev_hash, audit_logs.hash: rantai hash internal untuk mendeteksi perubahan audit log",
+	"data mahasiswa lain (misalnya mahasiswa bimbingan dosen) yang bukan milik user ini",
+}
+
+// Tambahan PersonalDataExcluded pada export mandiri, karena audit log juga memuat data pihak lain
+var PersonalDataExcludedSelf = []string{
+	"audit_logs.ip, audit_logs.user_agent pada entry yang dilakukan atau diimpersonasi pihak lain",
+	"audit_logs.before, audit_logs.after pada entry yang menyasar data pihak lain (misalnya user atau prestasi lain)",
+}
@@ -0,0 +1,27 @@
+package models
+
+// Scope permission pada role_permissions
+const (
+	ScopeOwn        = "own"
+	ScopeAdvisees   = "advisees"
+	ScopeDepartment = "department"
+	ScopeAll        = "all"
+)
+
+var PermissionScopes = []string{ScopeOwn, ScopeAdvisees, ScopeDepartment, ScopeAll}
+
+// PolicySubject adalah identitas user yang sedang mengakses (actor)
+type PolicySubject struct {
+	UserID     string
+	StudentID  string
+	LecturerID string
+	Department string
+}
+
+// PolicyResource adalah pemilik dari resource yang diakses
+type PolicyResource struct {
+	StudentID  string
+	LecturerID string
+	AdvisorID  string
+	Department string
+}
@@ -0,0 +1,19 @@
+package models
+
+import "uas/app/apperror"
+
+// ErrorResponse adalah envelope seragam untuk seluruh respons error.
+// Field message di level atas dipertahankan untuk client lama yang hanya membaca "message".
+type ErrorResponse struct {
+	Success   bool        `json:"success"`
+	Message   string      `json:"message"`
+	Error     ErrorDetail `json:"error"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+type ErrorDetail struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Fields  []apperror.FieldError  `json:"fields,omitempty"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
@@ -7,14 +7,34 @@ import (
 )
 
 type Role struct {
-	ID          uuid.UUID
-	Name        string
-	Description string
-	CreatedAt   time.Time
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RoleDetail struct {
+	Role
+	Permissions []Permission `json:"permissions"`
+}
+
+type CreateRoleRequest struct {
+	Name        string `json:"name" validate:"required,notblank,max=50"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+// Scope wajib diisi agar grant tidak diam-diam berlaku untuk seluruh data
+type GrantPermissionRequest struct {
+	PermissionID string `json:"permission_id" validate:"required,uuid"`
+	Scope        string `json:"scope" validate:"required,scope"`
 }
 
 const (
 	RoleAdmin     = "Admin"
 	RoleMahasiswa = "Mahasiswa"
 	RoleDosen     = "Dosen Wali"
-)
\ No newline at end of file
+)
+
+// SystemRoles adalah role bawaan yang namanya dipakai langsung di kode,
+// sehingga tidak boleh diganti nama atau dihapus lewat API.
+var SystemRoles = []string{RoleAdmin, RoleMahasiswa, RoleDosen}
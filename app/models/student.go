@@ -9,9 +9,9 @@ import (
 type Student struct {
 	ID           uuid.UUID `json:"id"`
 	UserID       uuid.UUID `json:"user_id"`
-	StudentID    string    `json:"student_id"`
-	ProgramStudy string    `json:"program_study"`
-	AcademicYear string    `json:"academy_year"`
+	StudentID    string    `json:"student_id" validate:"required,nim"`
+	ProgramStudy string    `json:"program_study" validate:"required,max=100"`
+	AcademicYear string    `json:"academy_year" validate:"omitempty,numeric,len=4"`
 	AdvisorID    uuid.UUID `json:"advisor_id"`	
 	CreatedAt    time.Time `json:"created_at"`
 }
@@ -26,9 +26,62 @@ type GetStudent struct {
 	RoleName     string    `json:"role_name"`
 	ProgramStudy string    `json:"program_study"`
 	AcademyYear  string    `json:"academy_year"`
+	AdvisorID    string    `json:"advisor_id"`
+	AdvisorNIP   string    `json:"advisor_nip"`
+	AdvisorName  string    `json:"advisor_name"`
+	Status       string    `json:"status"`
 	IsActive     bool      `json:"is_active"`
 }
 
 type UpdateAdvisorRequest struct {
-	AdvisorID string `json:"advisor_id" validate:"required"`
-}
\ No newline at end of file
+	AdvisorID string `json:"advisor_id" validate:"required,uuid"`
+}
+
+// Status studi mahasiswa
+const (
+	StudentActive     = "active"
+	StudentOnLeave    = "on_leave"
+	StudentGraduated  = "graduated"
+	StudentDroppedOut = "dropped_out"
+)
+
+var StudentStatuses = []string{StudentActive, StudentOnLeave, StudentGraduated, StudentDroppedOut}
+
+// studentStatusTransitions berisi perpindahan status yang diizinkan; graduated dan dropped_out adalah status akhir
+var studentStatusTransitions = map[string][]string{
+	StudentActive:  {StudentOnLeave, StudentGraduated, StudentDroppedOut},
+	StudentOnLeave: {StudentActive, StudentDroppedOut},
+}
+
+// CanTransitionStudentStatus memeriksa apakah status mahasiswa boleh diubah dari from ke to
+func CanTransitionStudentStatus(from, to string) bool {
+	for _, next := range studentStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateStudentRequest mengoreksi data akademik mahasiswa
+type UpdateStudentRequest struct {
+	StudentID    string `json:"student_id" validate:"required,nim"`
+	ProgramStudy string `json:"program_study" validate:"required,notblank,max=100"`
+	AcademicYear string `json:"academy_year" validate:"omitempty,numeric,len=4"`
+}
+
+type UpdateStudentStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active on_leave graduated dropped_out"`
+	Reason string `json:"reason" validate:"required,notblank,max=500"`
+}
+
+type StudentStatusHistory struct {
+	ID            string    `json:"id"`
+	StudentID     string    `json:"student_id"`
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	Reason        string    `json:"reason"`
+	ChangedBy     string    `json:"changed_by"`
+	ChangedByName string    `json:"changed_by_name"`
+	CreatedAt     time.Time `json:"created_at"`
+}
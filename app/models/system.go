@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Status dependensi pada endpoint readiness
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type DependencyCheck struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type MigrationStatus struct {
+	Version uint   `json:"version"`
+	Dirty   bool   `json:"dirty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type ReadinessReport struct {
+	Status    string                     `json:"status"`
+	Checks    map[string]DependencyCheck `json:"checks"`
+	Migration MigrationStatus            `json:"migration"`
+}
+
+// ReadinessSummary adalah bentuk publik ReadinessReport untuk /readyz: up/down per pengecekan serta versi
+// migration, tanpa pesan error driver yang dapat membocorkan host, potongan DSN atau SQL state
+type ReadinessSummary struct {
+	Status           string            `json:"status"`
+	Checks           map[string]string `json:"checks"`
+	MigrationVersion uint              `json:"migration_version"`
+	Dirty            bool              `json:"dirty"`
+}
+
+func (r ReadinessReport) Summary() ReadinessSummary {
+	summary := ReadinessSummary{
+		Status:           r.Status,
+		Checks:           map[string]string{},
+		MigrationVersion: r.Migration.Version,
+		Dirty:            r.Migration.Dirty,
+	}
+	for name, dep := range r.Checks {
+		summary.Checks[name] = dep.Status
+	}
+	summary.Checks["migration"] = StatusUp
+	if r.Migration.Error != "" || r.Migration.Dirty {
+		summary.Checks["migration"] = StatusDown
+	}
+	return summary
+}
+
+type PostgresPoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+type MongoServerInfo struct {
+	Version    string `json:"version"`
+	GitVersion string `json:"git_version,omitempty"`
+	Database   string `json:"database"`
+}
+
+type SystemStatus struct {
+	AppName   string            `json:"app_name"`
+	Env       string            `json:"env"`
+	Version   string            `json:"version"`
+	StartedAt time.Time         `json:"started_at"`
+	Uptime    string            `json:"uptime"`
+	Readiness ReadinessReport   `json:"readiness"`
+	Postgres  PostgresPoolStats `json:"postgres"`
+	Mongo     *MongoServerInfo  `json:"mongo,omitempty"`
+}
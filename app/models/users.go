@@ -16,8 +16,32 @@ type User struct {
 	RoleID uuid.UUID `json:"role_id"`
 	RoleName string `json:"role_name"`
 	IsActive bool `json:"is_active"`
+	PreferredLanguage string `json:"preferred_language,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
+}
+
+// Mode penghapusan user pada DELETE /users/:id
+const (
+	DeleteSoft      = "soft"
+	DeleteHard      = "hard"
+	DeleteAnonymize = "anonymize"
+)
+
+var UserDeleteModes = []string{DeleteSoft, DeleteHard, DeleteAnonymize}
+
+// UserDependencies adalah data yang masih merujuk user. Selama ada yang bernilai lebih dari nol,
+// user tidak dapat di-hard delete; mahasiswa bimbingan juga menghalangi anonimisasi.
+type UserDependencies struct {
+	Achievements  int `json:"achievements"`
+	Advisees      int `json:"advisees"`
+	Verifications int `json:"verifications"`
+}
+
+func (d UserDependencies) Blocking() bool {
+	return d.Achievements > 0 || d.Advisees > 0 || d.Verifications > 0
 }
 
 type UserResponseDTO struct {
@@ -29,11 +53,11 @@ type UserResponseDTO struct {
 
 type CreateUserRequest struct {
 	// Data User
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	FullName string `json:"full_name"`
-	RoleID   string `json:"role_id"`
+	Username string `json:"username" validate:"required,username"`
+	Email    string `json:"email" validate:"required,email,max=100"`
+	Password string `json:"password" validate:"required,password,max=72"`
+	FullName string `json:"full_name" validate:"required,notblank,max=100"`
+	RoleID   string `json:"role_id" validate:"required,uuid"`
 	RoleName string `json:"role_name"`
 	Student *Student `json:"student"` 
 	Lecture *Lecture `json:"lecture"`
@@ -50,20 +74,20 @@ type CreateUser struct {
 }
 
 type UpdateUser struct {
-	Username string `json:"username"`
-	Email string `json:"email"`
-	FullName string `json:"full_name"`
-	RoleID uuid.UUID `json:"role_id"`
+	Username string `json:"username" validate:"required,username"`
+	Email string `json:"email" validate:"required,email,max=100"`
+	FullName string `json:"full_name" validate:"required,notblank,max=100"`
 	IsActive bool `json:"is_active"`
 }
 
 type UpdateRole struct {
-    RoleID string `json:"role_id"`
+    RoleID string `json:"role_id" validate:"required,uuid"`
 }
 
 type LoginRequest struct { 
-	Username string `json:"username"` 
-	Password string `json:"password"` 
+	Username string `json:"username" validate:"required"` 
+	Password string `json:"password" validate:"required"` 
+	Device   string `json:"device"`
 }
 
 type LoginResponse struct { 
@@ -76,9 +100,28 @@ type JWTClaims struct {
 	UserID   uuid.UUID  `json:"user_id"` 
 	Username string `json:"username"` 
 	RoleName string `json:"role_name"` 
+	Actor    *ActorClaim `json:"act,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+	Language string `json:"lang,omitempty"` // preferensi bahasa user, menggantikan Accept-Language
 	jwt.RegisteredClaims
 }
 
+// ActorClaim adalah admin yang sebenarnya memakai token impersonasi (RFC 8693 "act")
+type ActorClaim struct {
+	UserID   uuid.UUID `json:"sub"`
+	Username string    `json:"username"`
+}
+
+type ImpersonateRequest struct {
+	DurationMinutes int    `json:"duration_minutes" validate:"omitempty,min=1,max=60"`
+	Reason          string `json:"reason" validate:"max=255"`
+}
+
 type RefreshTokenRequest struct {
-    RefreshToken string `json:"refreshToken"`
+    RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// UpdatePreferencesRequest adalah body PUT /auth/preferences
+type UpdatePreferencesRequest struct {
+    Language string `json:"language" validate:"required,oneof=id en"`
 }
\ No newline at end of file
@@ -14,16 +14,15 @@ import (
 
 type AchievementRepository interface {
 	GetStudentIDByUserID(ctx context.Context, userID string) (string, error)
+	GetStudentStatus(ctx context.Context, studentID string) (string, error)
 	CreateAchievementMongo(ctx context.Context, data models.AchievementMongo) (string, error)
 	CreateAchievementReference(ctx context.Context, ref models.AchievementReference) error
 	GetAchievementByID(ctx context.Context, id string) (models.AchievementReference, error)
     UpdateAchievement(ctx context.Context, pgID string, mongoID string, data models.AchievementMongo) error
     SoftDeleteAchievement(ctx context.Context, pgID string, mongoID string) error
 	SubmitAchievement(ctx context.Context, id string) error
-    GetLecturerIDByUserID(ctx context.Context, userID string) (string, error)
     VerifyAchievement(ctx context.Context, id string, verifierUserID string) error
     RejectAchievement(ctx context.Context, id string, verifierUserID string, note string) error
-    CheckStudentAdvisorRelationship(ctx context.Context, lecturerID string, studentID string) (bool, error)
 }
 
 type achievementRepository struct {
@@ -40,11 +39,21 @@ func (r *achievementRepository) GetStudentIDByUserID(ctx context.Context, userID
 	var studentID string
 	err := r.pg.QueryRowContext(ctx, query, userID).Scan(&studentID)
 	if err != nil {
-		return "", err
+		return "", errStudentNotFound(err)
 	}
 	return studentID, nil
 }
 
+// GetStudentStatus mengambil status studi mahasiswa (active, on_leave, graduated, dropped_out)
+func (r *achievementRepository) GetStudentStatus(ctx context.Context, studentID string) (string, error) {
+	var status string
+	err := r.pg.QueryRowContext(ctx, `SELECT status FROM students WHERE id = $1`, studentID).Scan(&status)
+	if err != nil {
+		return "", errStudentNotFound(err)
+	}
+	return status, nil
+}
+
 // Simpan ke MongoDB
 func (r *achievementRepository) CreateAchievementMongo(ctx context.Context, data models.AchievementMongo) (string, error) {
 	collection := r.mongo.Collection("achievements")
@@ -66,8 +75,8 @@ func (r *achievementRepository) CreateAchievementMongo(ctx context.Context, data
 func (r *achievementRepository) CreateAchievementReference(ctx context.Context, ref models.AchievementReference) error {
 	query := `
 		INSERT INTO achievement_references (
-			id, student_id, mongo_achievement_id, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $5)
+			id, student_id, mongo_achievement_id, status, created_at, updated_at, deleted_at
+		) VALUES ($1, $2, $3, $4, $5, $5, NULL)
 	`
 	_, err := r.pg.ExecContext(ctx, query, ref.ID, ref.StudentID, ref.MongoAchievementID, "draft", time.Now())
 	if err != nil {
@@ -86,7 +95,7 @@ func (r *achievementRepository) GetAchievementByID(ctx context.Context, id strin
     var ref models.AchievementReference    
     err := r.pg.QueryRowContext(ctx, query, id).Scan(&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status)
     if err != nil {
-        return models.AchievementReference{}, err
+        return models.AchievementReference{}, errAchievementNotFound(err)
     }
     return ref, nil
 }
@@ -117,7 +126,8 @@ func (r *achievementRepository) UpdateAchievement(ctx context.Context, pgID stri
     queryPG := `UPDATE achievement_references SET updated_at = NOW() WHERE id = $1`
     _, err = r.pg.ExecContext(ctx, queryPG, pgID)
     if err != nil {
-        return fmt.Errorf("gagal update postgres: %w", err)
+        return logError(ctx, "prestasi sudah diupdate di MongoDB tetapi gagal di PostgreSQL", fmt.Errorf("gagal update postgres: %w", err),
+            "achievement_id", pgID, "mongo_id", mongoID)
     }
 
     return nil
@@ -138,7 +148,11 @@ func (r *achievementRepository) SoftDeleteAchievement(ctx context.Context, pgID
     update := bson.M{"$set": bson.M{"deletedAt": time.Now()}}
     
     _, err = r.mongo.Collection("achievements").UpdateOne(ctx, filter, update)
-    return err
+    if err != nil {
+        return logError(ctx, "prestasi sudah dihapus di PostgreSQL tetapi gagal di MongoDB", fmt.Errorf("gagal soft delete mongo: %w", err),
+            "achievement_id", pgID, "mongo_id", mongoID)
+    }
+    return nil
 }
 
 func (r *achievementRepository) SubmitAchievement(ctx context.Context, id string) error {
@@ -163,16 +177,6 @@ func (r *achievementRepository) SubmitAchievement(ctx context.Context, id string
     return nil
 }
 
-func (r *achievementRepository) GetLecturerIDByUserID(ctx context.Context, userID string) (string, error) {
-    query := `SELECT id FROM lecturers WHERE user_id = $1`
-    var lecturerID string
-    err := r.pg.QueryRowContext(ctx, query, userID).Scan(&lecturerID)
-    if err != nil {
-        return "", err
-    }
-    return lecturerID, nil
-}
-
 func (r *achievementRepository) VerifyAchievement(ctx context.Context, id string, verifierUserID string) error {
     query := `
         UPDATE achievement_references 
@@ -214,15 +218,3 @@ func (r *achievementRepository) RejectAchievement(ctx context.Context, id string
     }
     return nil
 }
-
-func (r *achievementRepository) CheckStudentAdvisorRelationship(ctx context.Context, lecturerID string, studentID string) (bool, error) {
-    query := `SELECT count(1) FROM students WHERE id = $1 AND advisor_id = $2`
-    
-    var count int
-    err := r.pg.QueryRowContext(ctx, query, studentID, lecturerID).Scan(&count)
-    if err != nil {
-        return false, err
-    }
-    
-    return count > 0, nil
-}
\ No newline at end of file
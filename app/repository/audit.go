@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"uas/app/models"
+	"uas/utils"
+
+	"github.com/google/uuid"
+)
+
+// Key advisory lock untuk menserialisasi penulisan hash chain audit log
+const auditChainLockKey = 7208028
+
+type AuditRepository interface {
+	Append(ctx context.Context, entry models.AuditLog) error
+	List(ctx context.Context, filter models.AuditFilter) ([]models.AuditLog, int, error)
+	VerifyChain(ctx context.Context) (models.AuditChainStatus, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Append(ctx context.Context, entry models.AuditLog) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi audit: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := appendAuditTx(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// appendAuditTx menyambung entry ke hash chain di dalam transaksi pemanggil
+func appendAuditTx(ctx context.Context, tx *sql.Tx, entry models.AuditLog) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockKey); err != nil {
+		return fmt.Errorf("gagal mengunci audit chain: %w", err)
+	}
+
+	var prevHash string
+	err := tx.QueryRowContext(ctx, `SELECT hash FROM audit_logs ORDER BY id DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("gagal mengambil hash terakhir: %w", err)
+	}
+
+	// Presisi timestamp Postgres adalah mikrodetik
+	entry.CreatedAt = entry.CreatedAt.UTC().Truncate(time.Microsecond)
+	entry.Before = utils.CanonicalJSON(entry.Before)
+	entry.After = utils.CanonicalJSON(entry.After)
+	entry.PrevHash = prevHash
+	entry.PIIHash = utils.HashAuditPII(entry)
+	entry.Hash = utils.HashAuditEntry(entry)
+
+	query := `
+		INSERT INTO audit_logs (
+			actor_id, actor_username, action, target_type, target_id,
+			before, after, ip, user_agent, request_id, prev_hash, hash, created_at,
+			impersonator_id, impersonator_username, pii_hash
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		nullString(entry.ActorID),
+		entry.ActorUsername,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+		entry.IP,
+		entry.UserAgent,
+		entry.RequestID,
+		entry.PrevHash,
+		entry.Hash,
+		entry.CreatedAt,
+		nullString(entry.ImpersonatorID),
+		nullString(entry.ImpersonatorUsername),
+		entry.PIIHash,
+	)
+	if err != nil {
+		return fmt.Errorf("gagal insert audit log: %w", err)
+	}
+	return nil
+}
+
+// redactUserAuditTx menghapus data pribadi user dari audit log: username, IP dan user agent pada entry yang
+// dilakukan user (atau saat user melakukan impersonasi), serta snapshot before/after pada entry yang
+// menyasar akun atau profil mahasiswa/dosennya. Hash, prev_hash dan pii_hash tidak diubah; ID baris yang
+// diredaksi dikembalikan untuk dicatat pada entry user.anonymize sehingga VerifyChain dapat memeriksanya.
+func redactUserAuditTx(ctx context.Context, tx *sql.Tx, userID uuid.UUID) ([]int64, error) {
+	query := `
+		WITH targeted AS (
+			SELECT id FROM audit_logs
+			WHERE (target_type = 'user' AND target_id = $2)
+			   OR (target_type = 'student' AND target_id IN (SELECT id::text FROM students WHERE user_id = $1))
+			   OR (target_type = 'lecturer' AND target_id IN (SELECT id::text FROM lecturers WHERE user_id = $1))
+		)
+		UPDATE audit_logs SET
+			actor_username = CASE WHEN actor_id = $1 THEN NULL ELSE actor_username END,
+			ip = CASE WHEN actor_id = $1 THEN NULL ELSE ip END,
+			user_agent = CASE WHEN actor_id = $1 THEN NULL ELSE user_agent END,
+			impersonator_username = CASE WHEN impersonator_id = $1 THEN NULL ELSE impersonator_username END,
+			before = CASE WHEN id IN (SELECT id FROM targeted) THEN NULL ELSE before END,
+			after = CASE WHEN id IN (SELECT id FROM targeted) THEN NULL ELSE after END,
+			redacted_at = COALESCE(redacted_at, NOW())
+		WHERE actor_id = $1 OR impersonator_id = $1 OR id IN (SELECT id FROM targeted)
+		RETURNING id
+	`
+	rows, err := tx.QueryContext(ctx, query, userID, userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("gagal meredaksi audit log: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal scanning id audit: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditLog, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.ActorID != "" {
+		addCondition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.ImpersonatorID != "" {
+		addCondition("impersonator_id = $%d", filter.ImpersonatorID)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		addCondition("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		addCondition("target_id = $%d", filter.TargetID)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at <= $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT count(1) FROM audit_logs "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("gagal menghitung audit log: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_logs
+		%s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d
+	`, auditColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("gagal query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []models.AuditLog
+	for rows.Next() {
+		entry, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// VerifyChain menghitung ulang seluruh hash chain dan melaporkan entry pertama yang tidak cocok
+func (r *auditRepository) VerifyChain(ctx context.Context) (models.AuditChainStatus, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+auditColumns+" FROM audit_logs ORDER BY id ASC")
+	if err != nil {
+		return models.AuditChainStatus{}, fmt.Errorf("gagal query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	status := models.AuditChainStatus{Valid: true}
+	prevHash := ""
+	// Entry yang diredaksi hanya sah jika ID-nya tercatat pada entry user.anonymize yang muncul sesudahnya.
+	// Hash-nya tetap dihitung ulang dari kolom non-pribadi dan pii_hash; hanya entry lama tanpa pii_hash
+	// yang cukup diperiksa sambungan chain-nya.
+	unclaimed := map[int64]bool{}
+	for rows.Next() {
+		entry, err := scanAuditLog(rows)
+		if err != nil {
+			return models.AuditChainStatus{}, err
+		}
+		status.Checked++
+
+		valid := entry.PrevHash == prevHash
+		if entry.RedactedAt != nil {
+			status.Redacted++
+			unclaimed[entry.ID] = true
+			if entry.PIIHash != "" {
+				valid = valid && utils.HashAuditEntry(entry) == entry.Hash
+			}
+		} else {
+			valid = valid && utils.HashAuditEntry(entry) == entry.Hash
+			valid = valid && (entry.PIIHash == "" || utils.HashAuditPII(entry) == entry.PIIHash)
+		}
+		if !valid {
+			status.Valid = false
+			status.BrokenAt = entry.ID
+			return status, nil
+		}
+
+		if entry.Action == models.AuditUserAnonymize {
+			for _, id := range redactedAuditIDs(entry.After) {
+				delete(unclaimed, id)
+			}
+		}
+		prevHash = entry.Hash
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.AuditChainStatus{}, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	for id := range unclaimed {
+		if status.Valid || id < status.BrokenAt {
+			status.Valid = false
+			status.BrokenAt = id
+		}
+	}
+
+	return status, nil
+}
+
+// redactedAuditIDs membaca daftar ID audit log yang diredaksi dari after entry user.anonymize
+func redactedAuditIDs(after json.RawMessage) []int64 {
+	var payload map[string]json.RawMessage
+	if len(after) == 0 || json.Unmarshal(after, &payload) != nil {
+		return nil
+	}
+	var ids []int64
+	if json.Unmarshal(payload[models.AuditRedactedIDsKey], &ids) != nil {
+		return nil
+	}
+	return ids
+}
+
+const auditColumns = `
+	id, COALESCE(actor_id::text, ''), COALESCE(actor_username, ''), action, target_type,
+	COALESCE(target_id, ''), before, after, COALESCE(ip, ''), COALESCE(user_agent, ''),
+	COALESCE(request_id, ''), prev_hash, hash, created_at,
+	COALESCE(impersonator_id::text, ''), COALESCE(impersonator_username, ''), redacted_at,
+	COALESCE(pii_hash, '')
+`
+
+func scanAuditLog(rows *sql.Rows) (models.AuditLog, error) {
+	var entry models.AuditLog
+	var before, after []byte
+	err := rows.Scan(
+		&entry.ID,
+		&entry.ActorID,
+		&entry.ActorUsername,
+		&entry.Action,
+		&entry.TargetType,
+		&entry.TargetID,
+		&before,
+		&after,
+		&entry.IP,
+		&entry.UserAgent,
+		&entry.RequestID,
+		&entry.PrevHash,
+		&entry.Hash,
+		&entry.CreatedAt,
+		&entry.ImpersonatorID,
+		&entry.ImpersonatorUsername,
+		&entry.RedactedAt,
+		&entry.PIIHash,
+	)
+	if err != nil {
+		return models.AuditLog{}, fmt.Errorf("gagal scanning row audit: %w", err)
+	}
+
+	entry.Before = utils.CanonicalJSON(before)
+	entry.After = utils.CanonicalJSON(after)
+	return entry, nil
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullJSON(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
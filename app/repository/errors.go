@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"uas/app/apperror"
+	"uas/utils"
+)
+
+// logError mencatat error repository lewat logger dari context (sudah berisi request_id) lalu
+// mengembalikannya apa adanya. Dipakai untuk kegagalan yang tidak cukup dijelaskan oleh error
+// yang dikembalikan, misalnya penulisan yang sudah berhasil di salah satu database.
+func logError(ctx context.Context, msg string, err error, args ...any) error {
+	utils.Logger(ctx).Error(msg, append(args, "error", err)...)
+	return err
+}
+
+// notFound menerjemahkan sql.ErrNoRows menjadi error domain not found dengan kode spesifik
+func notFound(err error, code string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.Wrap(err, apperror.NotFound(code))
+	}
+	return err
+}
+
+// Error not found per entitas, dipakai bersama notFound maupun saat RowsAffected bernilai 0
+func errUserNotFound(err error) error {
+	return notFound(err, apperror.CodeUserNotFound)
+}
+
+func errRoleNotFound(err error) error {
+	return notFound(err, apperror.CodeRoleNotFound)
+}
+
+func errPermissionNotFound(err error) error {
+	return notFound(err, apperror.CodePermissionNotFound)
+}
+
+func errStudentNotFound(err error) error {
+	return notFound(err, apperror.CodeStudentNotFound)
+}
+
+func errLecturerNotFound(err error) error {
+	return notFound(err, apperror.CodeLecturerNotFound)
+}
+
+func errAchievementNotFound(err error) error {
+	return notFound(err, apperror.CodeAchievementNotFound)
+}
+
+func errSessionNotFound(err error) error {
+	return notFound(err, apperror.CodeSessionNotFound)
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"uas/app/apperror"
 	"uas/app/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func CreateLecture(tx *sql.Tx, lecture models.Lecture) error {
@@ -24,10 +28,29 @@ func CreateLecture(tx *sql.Tx, lecture models.Lecture) error {
 	return err
 }
 
+// lecturerConstraintError memetakan pelanggaran index unik profil dosen ke error domain
+func lecturerConstraintError(err error) error {
+	if !apperror.IsUniqueViolation(err) {
+		return err
+	}
+	switch apperror.ViolatedConstraint(err) {
+	case "uq_lecturers_lecturer_id":
+		return apperror.Wrap(err, apperror.Conflict(apperror.CodeLecturerNIPTaken))
+	case "uq_lecturers_user_id":
+		return apperror.Wrap(err, apperror.Conflict(apperror.CodeLecturerProfileExists))
+	}
+	return apperror.FromDatabase(err)
+}
+
 type LecturerRepository interface {
-	GetAllLecturersByRole(ctx context.Context, roleName string) ([]models.GetLecture, error)
+	ListLecturers(ctx context.Context, filter models.LecturerFilter) ([]models.GetLecture, models.ListMeta, error)
+	ExportLecturers(ctx context.Context, filter models.LecturerFilter, fn func(models.GetLecture) error) error
 	GetLecturerByID(ctx context.Context, id string) (models.GetLecture, error)
 	GetAdviseesByLecturerID(ctx context.Context, lecturerID string) ([]models.GetLecture, error)
+	GetLecturerIDsByNIP(ctx context.Context, nips []string) (map[string]uuid.UUID, error)
+	CreateLecturer(ctx context.Context, lecture models.Lecture) error
+	UpdateLecturer(ctx context.Context, id string, req models.UpdateLecturerRequest) error
+	ReassignAdvisees(ctx context.Context, fromLecturerID string, toLecturerID string, studentIDs []string) ([]string, error)
 }
 
 type lecturerRepository struct {
@@ -38,61 +61,97 @@ func NewLecturerRepository(db *sql.DB) LecturerRepository {
 	return &lecturerRepository{db: db}
 }
 
-func (r *lecturerRepository) GetAllLecturersByRole(ctx context.Context, roleName string) ([]models.GetLecture, error) {
-	query := `
-		SELECT 
+// ListLecturers mengambil daftar dosen wali dengan filter, pencarian (nama, NIP, email), sorting dan pagination
+func (r *lecturerRepository) ListLecturers(ctx context.Context, filter models.LecturerFilter) ([]models.GetLecture, models.ListMeta, error) {
+	q := lecturerListQuery(filter)
+
+	lecturers := []models.GetLecture{}
+	meta, err := q.run(ctx, r.db, lecturerListColumns, filter.ListParams,
+		func(rows *sql.Rows, sortValue *string) (string, error) {
+			l, err := scanListLecturer(rows, sortValue)
+			lecturers = append(lecturers, l)
+			return l.ID, err
+		})
+	if err != nil {
+		return nil, meta, fmt.Errorf("gagal mengambil daftar dosen: %w", err)
+	}
+
+	return lecturers, meta, nil
+}
+
+// ExportLecturers mengalirkan seluruh dosen wali sesuai filter ListLecturers (tanpa pagination) ke fn
+func (r *lecturerRepository) ExportLecturers(ctx context.Context, filter models.LecturerFilter, fn func(models.GetLecture) error) error {
+	q := lecturerListQuery(filter)
+
+	return q.stream(ctx, r.db, lecturerListColumns, filter.ListParams, func(rows *sql.Rows) error {
+		l, err := scanListLecturer(rows)
+		if err != nil {
+			return fmt.Errorf("gagal scanning row: %w", err)
+		}
+		return fn(l)
+	})
+}
+
+const lecturerListColumns = `
 			l.id, 
 			l.user_id, 
-			l.lecturer_id, 
-			l.department, 
+			COALESCE(l.lecturer_id, ''), 
+			COALESCE(l.department, ''), 
 			u.full_name, 
 			u.username, 
 			u.email, 
 			u.is_active,
 			l.created_at,
-			r.name as role_name
-		FROM lecturers l
-		JOIN users u ON l.user_id = u.id
-		JOIN roles r ON u.role_id = r.id
-		WHERE r.name = $1
-		ORDER BY u.full_name ASC
-	`
+			r.name`
 
-	rows, err := r.db.QueryContext(ctx, query, roleName)
-	if err != nil {
-		return nil, fmt.Errorf("gagal query lecturers: %w", err)
+func lecturerListQuery(filter models.LecturerFilter) listQuery {
+	q := listQuery{
+		from: `FROM lecturers l
+		JOIN users u ON l.user_id = u.id
+		JOIN roles r ON u.role_id = r.id`,
+		idColumn: "l.id",
+		sortColumn: map[string]string{
+			"full_name":  "u.full_name",
+			"nip":        "COALESCE(l.lecturer_id, '')",
+			"department": "COALESCE(l.department, '')",
+			"created_at": "l.created_at",
+		},
 	}
-	defer rows.Close()
 
-	var lecturers []models.GetLecture
-
-	for rows.Next() {
-		var l models.GetLecture
-		err := rows.Scan(
-			&l.ID,
-			&l.UserID,
-			&l.LecturerID,
-			&l.Department,
-			&l.FullName,
-			&l.Username,
-			&l.Email,
-			&l.IsActive,
-			&l.CreatedAt,
-			&l.RoleName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("gagal scanning row dosen: %w", err)
-		}
-		lecturers = append(lecturers, l)
+	q.where("r.name = $%d", models.RoleDosen)
+	q.search(filter.Search, "u.full_name", "l.lecturer_id", "u.email")
+	if filter.Department != "" {
+		q.where("l.department = $%d", filter.Department)
+	}
+	if filter.IsActive != nil {
+		q.where("u.is_active = $%d", *filter.IsActive)
 	}
+	return q
+}
 
-	return lecturers, nil
+// scanListLecturer membaca kolom lecturerListColumns; extra untuk kolom tambahan (nilai sort)
+func scanListLecturer(rows *sql.Rows, extra ...interface{}) (models.GetLecture, error) {
+	var l models.GetLecture
+	dest := []interface{}{
+		&l.ID,
+		&l.UserID,
+		&l.LecturerID,
+		&l.Department,
+		&l.FullName,
+		&l.Username,
+		&l.Email,
+		&l.IsActive,
+		&l.CreatedAt,
+		&l.RoleName,
+	}
+	err := rows.Scan(append(dest, extra...)...)
+	return l, err
 }
 
 func (r *lecturerRepository) GetLecturerByID(ctx context.Context, id string) (models.GetLecture, error) {
 	query := `
 		SELECT 
-			l.id, l.user_id, l.lecturer_id, l.department, 
+			l.id, l.user_id, COALESCE(l.lecturer_id, ''), COALESCE(l.department, ''), 
 			u.full_name, u.username, u.email, u.is_active, l.created_at,
 			r.name
 		FROM lecturers l
@@ -109,7 +168,7 @@ func (r *lecturerRepository) GetLecturerByID(ctx context.Context, id string) (mo
 	)
 
 	if err != nil {
-		return models.GetLecture{}, err
+		return models.GetLecture{}, errLecturerNotFound(err)
 	}
 
 	return l, nil
@@ -165,4 +224,100 @@ func (r *lecturerRepository) GetAdviseesByLecturerID(ctx context.Context, lectur
 	}
 
 	return students, nil
-}
\ No newline at end of file
+}
+
+// GetLecturerIDsByNIP memetakan NIP ke id dosen untuk NIP yang terdaftar
+func (r *lecturerRepository) GetLecturerIDsByNIP(ctx context.Context, nips []string) (map[string]uuid.UUID, error) {
+	ids := map[string]uuid.UUID{}
+	if len(nips) == 0 {
+		return ids, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT lecturer_id, id FROM lecturers WHERE lecturer_id = ANY($1)`, pq.Array(nips))
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencari dosen berdasarkan NIP: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nip string
+		var id uuid.UUID
+		if err := rows.Scan(&nip, &id); err != nil {
+			return nil, fmt.Errorf("gagal membaca data dosen: %w", err)
+		}
+		ids[nip] = id
+	}
+	return ids, rows.Err()
+}
+
+// CreateLecturer membuat profil dosen untuk user yang sudah ada
+func (r *lecturerRepository) CreateLecturer(ctx context.Context, lecture models.Lecture) error {
+	query := `
+		INSERT INTO lecturers (id, user_id, lecturer_id, department, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, lecture.ID, lecture.UserID, lecture.LectureID, lecture.Department, lecture.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan data dosen: %w", lecturerConstraintError(err))
+	}
+	return nil
+}
+
+// UpdateLecturer mengubah NIP dan departemen; NIP yang sudah dipakai dosen lain menjadi LECTURER_NIP_TAKEN
+func (r *lecturerRepository) UpdateLecturer(ctx context.Context, id string, req models.UpdateLecturerRequest) error {
+	query := `
+		UPDATE lecturers
+		SET lecturer_id = $1, department = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, req.LecturerID, req.Department, id)
+	if err != nil {
+		return fmt.Errorf("gagal update data dosen: %w", lecturerConstraintError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errLecturerNotFound(sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// ReassignAdvisees memindahkan mahasiswa bimbingan fromLecturerID ke toLecturerID dan mengembalikan id mahasiswa
+// yang dipindahkan. studentIDs kosong berarti seluruh mahasiswa bimbingan; id yang bukan bimbingan diabaikan.
+func (r *lecturerRepository) ReassignAdvisees(ctx context.Context, fromLecturerID string, toLecturerID string, studentIDs []string) ([]string, error) {
+	query := `
+		UPDATE students
+		SET advisor_id = $1, updated_at = NOW()
+		WHERE advisor_id = $2
+	`
+	args := []interface{}{toLecturerID, fromLecturerID}
+	if len(studentIDs) > 0 {
+		query += ` AND id = ANY($3)`
+		args = append(args, pq.Array(studentIDs))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+` RETURNING id`, args...)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return nil, apperror.Wrap(err, apperror.NotFound(apperror.CodeAdvisorNotFound))
+		}
+		return nil, fmt.Errorf("gagal memindahkan mahasiswa bimbingan: %w", err)
+	}
+	defer rows.Close()
+
+	moved := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal membaca mahasiswa bimbingan: %w", err)
+		}
+		moved = append(moved, id)
+	}
+	return moved, rows.Err()
+}
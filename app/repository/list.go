@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"uas/app/models"
+)
+
+// listQuery menyusun query daftar dengan filter, pencarian, sorting dan pagination (offset maupun cursor)
+type listQuery struct {
+	from       string            // FROM ... JOIN ...
+	idColumn   string            // kolom id unik sebagai pemecah urutan yang sama
+	sortColumn map[string]string // nama sort publik -> ekspresi SQL
+	conditions []string
+	args       []interface{}
+}
+
+// where menambahkan kondisi; %d pada clause diganti nomor parameter value
+func (q *listQuery) where(clause string, value interface{}) {
+	q.args = append(q.args, value)
+	q.conditions = append(q.conditions, strings.ReplaceAll(clause, "%d", fmt.Sprint(len(q.args))))
+}
+
+// search mencari term (case-insensitive, sebagian kata) pada salah satu kolom
+func (q *listQuery) search(term string, columns ...string) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+	q.args = append(q.args, "%"+escaped+"%")
+
+	clauses := make([]string, len(columns))
+	for i, col := range columns {
+		clauses[i] = fmt.Sprintf("%s ILIKE $%d", col, len(q.args))
+	}
+	q.conditions = append(q.conditions, "("+strings.Join(clauses, " OR ")+")")
+}
+
+func (q *listQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// run menghitung total lalu mengambil satu halaman. scan menerima rows dan mengembalikan id serta nilai sort
+// baris tersebut (untuk next cursor); kolom terakhir pada select selalu nilai sort sebagai text.
+func (q *listQuery) run(ctx context.Context, db *sql.DB, columns string, params models.ListParams,
+	scan func(rows *sql.Rows, sortValue *string) (string, error)) (models.ListMeta, error) {
+
+	meta := models.ListMeta{Limit: params.Limit, Sort: params.Sort, Order: params.Order}
+
+	if err := db.QueryRowContext(ctx, "SELECT count(1) "+q.from+" "+q.whereClause(), q.args...).Scan(&meta.Total); err != nil {
+		return meta, fmt.Errorf("gagal menghitung data: %w", err)
+	}
+
+	sortExpr := q.sortColumn[params.Sort]
+	direction, cmp := "ASC", ">"
+	if params.Order == models.OrderDesc {
+		direction, cmp = "DESC", "<"
+	}
+
+	args := append([]interface{}{}, q.args...)
+	conditions := append([]string{}, q.conditions...)
+	offset := 0
+
+	if params.Cursor != nil {
+		args = append(args, params.Cursor.Value, params.Cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, %s) %s ($%d, $%d)", sortExpr, q.idColumn, cmp, len(args)-1, len(args)))
+	} else {
+		meta.Page = params.Page
+		offset = (params.Page - 1) * params.Limit
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Ambil satu baris lebih untuk mengetahui apakah masih ada halaman berikutnya
+	args = append(args, params.Limit+1, offset)
+	query := fmt.Sprintf(`SELECT %s, (%s)::text %s %s ORDER BY %s %s, %s %s LIMIT $%d OFFSET $%d`,
+		columns, sortExpr, q.from, where, sortExpr, direction, q.idColumn, direction, len(args)-1, len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return meta, fmt.Errorf("gagal query data: %w", err)
+	}
+	defer rows.Close()
+
+	var last models.Cursor
+	count := 0
+	for rows.Next() {
+		count++
+		if count > params.Limit {
+			meta.NextCursor = models.Cursor{Sort: params.Sort, Order: params.Order, Value: last.Value, ID: last.ID}.Encode()
+			break
+		}
+		if last.ID, err = scan(rows, &last.Value); err != nil {
+			return meta, fmt.Errorf("gagal scanning row: %w", err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return meta, fmt.Errorf("error iterasi rows: %w", err)
+	}
+	return meta, nil
+}
+
+// stream mengambil seluruh data sesuai filter dan urutan params tanpa pagination.
+// Baris dibaca satu per satu dari koneksi dan diteruskan ke scan, sehingga data tidak ditampung di memori.
+func (q *listQuery) stream(ctx context.Context, db *sql.DB, columns string, params models.ListParams,
+	scan func(rows *sql.Rows) error) error {
+
+	sortExpr := q.sortColumn[params.Sort]
+	direction := "ASC"
+	if params.Order == models.OrderDesc {
+		direction = "DESC"
+	}
+
+	query := fmt.Sprintf(`SELECT %s %s %s ORDER BY %s %s, %s %s`,
+		columns, q.from, q.whereClause(), sortExpr, direction, q.idColumn, direction)
+
+	rows, err := db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return fmt.Errorf("gagal query data: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterasi rows: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"uas/app/models"
+
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PersonalDataRepository interface {
+	CollectPersonalData(ctx context.Context, userID string) ([]models.PersonalDataSection, error)
+}
+
+type personalDataRepository struct {
+	pg    *sql.DB
+	mongo *mongo.Database
+}
+
+func NewPersonalDataRepository(pg *sql.DB, mongo *mongo.Database) PersonalDataRepository {
+	return &personalDataRepository{pg: pg, mongo: mongo}
+}
+
+// CollectPersonalData mengumpulkan seluruh data yang disimpan tentang satu user dari PostgreSQL dan MongoDB,
+// termasuk data yang sudah di-soft delete. Kolom dibaca apa adanya sehingga kolom baru ikut terexport.
+func (r *personalDataRepository) CollectPersonalData(ctx context.Context, userID string) ([]models.PersonalDataSection, error) {
+	user, err := r.queryRecords(ctx, `
+		SELECT u.id, u.username, u.email, u.full_name, r.name AS role_name, u.is_active, u.preferred_language,
+			u.created_at, u.updated_at, u.deleted_at, u.anonymized_at
+		FROM users u
+		LEFT JOIN roles r ON u.role_id = r.id
+		WHERE u.id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(user) == 0 {
+		return nil, errUserNotFound(sql.ErrNoRows)
+	}
+
+	student, err := r.queryRecords(ctx, `SELECT * FROM students WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	lecturer, err := r.queryRecords(ctx, `SELECT * FROM lecturers WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	studentID, lecturerID := recordID(student), recordID(lecturer)
+
+	statusHistory, err := r.queryRecords(ctx, `
+		SELECT * FROM student_status_history WHERE student_id = NULLIF($1, '')::uuid ORDER BY created_at`, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := r.queryRecords(ctx, `
+		SELECT * FROM achievement_references WHERE student_id = NULLIF($1, '')::uuid ORDER BY created_at`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	var referenceIDs, mongoIDs []string
+	for _, ref := range references {
+		rec := ref.(map[string]interface{})
+		referenceIDs = append(referenceIDs, fmt.Sprint(rec["id"]))
+		mongoIDs = append(mongoIDs, fmt.Sprint(rec["mongo_achievement_id"]))
+	}
+
+	achievements, attachments, err := r.achievementDocuments(ctx, studentID, mongoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := r.queryRecords(ctx, `
+		SELECT id, device, ip, user_agent, created_at, last_seen_at, expires_at, revoked_at
+		FROM user_sessions WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogs, err := r.queryRecords(ctx, `
+		SELECT id, actor_id, actor_username, action, target_type, target_id, before, after, ip, user_agent,
+			request_id, impersonator_id, impersonator_username, created_at
+		FROM audit_logs
+		WHERE actor_id = $1::uuid
+		   OR impersonator_id = $1::uuid
+		   OR (target_type = 'user' AND target_id = $1::text)
+		   OR (target_type = 'student' AND target_id = NULLIF($2, ''))
+		   OR (target_type = 'lecturer' AND target_id = NULLIF($3, ''))
+		   OR (target_type = 'achievement' AND target_id = ANY($4))
+		ORDER BY id`, userID, studentID, lecturerID, pq.Array(referenceIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.PersonalDataSection{
+		{Name: "user", Description: "Akun pengguna", Records: user},
+		{Name: "student", Description: "Profil mahasiswa", Records: student},
+		{Name: "lecturer", Description: "Profil dosen", Records: lecturer},
+		{Name: "student_status_history", Description: "Riwayat status studi mahasiswa", Records: statusHistory},
+		{Name: "achievement_references", Description: "Referensi dan status verifikasi prestasi (PostgreSQL)", Records: references},
+		{Name: "achievements", Description: "Dokumen detail prestasi (MongoDB, Extended JSON)", Records: achievements},
+		{Name: "attachments", Description: "Lampiran yang tercatat pada dokumen prestasi", Records: attachments},
+		{Name: "sessions", Description: "Sesi login beserta perangkat dan alamat IP", Records: sessions},
+		{Name: "audit_logs", Description: "Audit log yang dilakukan oleh atau mengenai user ini", Records: auditLogs},
+	}, nil
+}
+
+// achievementDocuments mengambil dokumen prestasi milik mahasiswa (termasuk yang di-soft delete) sebagai
+// Extended JSON, beserta lampiran yang tercatat pada field attachments setiap dokumen
+func (r *personalDataRepository) achievementDocuments(ctx context.Context, studentID string, mongoIDs []string) ([]interface{}, []interface{}, error) {
+	documents, attachments := []interface{}{}, []interface{}{}
+	if studentID == "" {
+		return documents, attachments, nil
+	}
+
+	oids := []primitive.ObjectID{}
+	for _, id := range mongoIDs {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+
+	filter := bson.M{"$or": bson.A{bson.M{"_id": bson.M{"$in": oids}}, bson.M{"studentId": studentID}}}
+	cursor, err := r.mongo.Collection("achievements").Find(ctx, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal mengambil dokumen prestasi: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		doc, err := bson.MarshalExtJSON(cursor.Current, false, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gagal membaca dokumen prestasi: %w", err)
+		}
+		documents = append(documents, json.RawMessage(doc))
+
+		if files, err := cursor.Current.LookupErr("attachments"); err == nil {
+			entry, err := bson.MarshalExtJSON(bson.D{
+				{Key: "achievement_id", Value: cursor.Current.Lookup("_id")},
+				{Key: "attachments", Value: files},
+			}, false, false)
+			if err != nil {
+				return nil, nil, fmt.Errorf("gagal membaca lampiran prestasi: %w", err)
+			}
+			attachments = append(attachments, json.RawMessage(entry))
+		}
+	}
+
+	return documents, attachments, cursor.Err()
+}
+
+// queryRecords membaca seluruh baris menjadi map nama kolom ke nilai. UUID dan teks dari driver berupa
+// []byte sehingga diubah menjadi string, sedangkan kolom JSON/JSONB dipertahankan sebagai JSON.
+func (r *personalDataRepository) queryRecords(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	rows, err := r.pg.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data pribadi: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("gagal scanning row: %w", err)
+		}
+
+		record := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			value := values[i]
+			if b, ok := value.([]byte); ok {
+				switch col.DatabaseTypeName() {
+				case "JSON", "JSONB":
+					value = json.RawMessage(b)
+				default:
+					value = string(b)
+				}
+			}
+			record[col.Name()] = value
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// recordID mengembalikan kolom id dari record pertama, kosong jika tidak ada
+func recordID(records []interface{}) string {
+	if len(records) == 0 {
+		return ""
+	}
+	id, _ := records[0].(map[string]interface{})["id"].(string)
+	return id
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"uas/app/models"
+)
+
+type PolicyRepository interface {
+	GetPermissionScope(ctx context.Context, userID string, permissionName string) (string, error)
+	GetSubject(ctx context.Context, userID string) (models.PolicySubject, error)
+	GetAchievementResource(ctx context.Context, achievementID string) (models.PolicyResource, error)
+	GetStudentResource(ctx context.Context, studentID string) (models.PolicyResource, error)
+	GetLecturerResource(ctx context.Context, lecturerID string) (models.PolicyResource, error)
+}
+
+type policyRepository struct {
+	db *sql.DB
+}
+
+func NewPolicyRepository(db *sql.DB) PolicyRepository {
+	return &policyRepository{db: db}
+}
+
+// GetPermissionScope mengembalikan scope permission milik role user, sql.ErrNoRows jika tidak dimiliki
+func (r *policyRepository) GetPermissionScope(ctx context.Context, userID string, permissionName string) (string, error) {
+	query := `
+		SELECT rp.scope
+		FROM users u
+		JOIN role_permissions rp ON u.role_id = rp.role_id
+		JOIN permissions p ON rp.permission_id = p.id
+		WHERE u.id = $1
+		  AND p.name = $2
+	`
+
+	var scope string
+	err := r.db.QueryRowContext(ctx, query, userID, permissionName).Scan(&scope)
+	if err != nil {
+		return "", err
+	}
+	return scope, nil
+}
+
+// GetSubject mengambil identitas mahasiswa/dosen dari user yang sedang login
+func (r *policyRepository) GetSubject(ctx context.Context, userID string) (models.PolicySubject, error) {
+	query := `
+		SELECT
+			COALESCE(s.id::text, ''),
+			COALESCE(l.id::text, ''),
+			COALESCE(l.department, '')
+		FROM users u
+		LEFT JOIN students s ON s.user_id = u.id
+		LEFT JOIN lecturers l ON l.user_id = u.id
+		WHERE u.id = $1
+	`
+
+	subject := models.PolicySubject{UserID: userID}
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&subject.StudentID, &subject.LecturerID, &subject.Department)
+	if err != nil {
+		return models.PolicySubject{}, err
+	}
+	return subject, nil
+}
+
+func (r *policyRepository) GetAchievementResource(ctx context.Context, achievementID string) (models.PolicyResource, error) {
+	query := `
+		SELECT
+			ar.student_id::text,
+			COALESCE(s.advisor_id::text, ''),
+			COALESCE(l.department, '')
+		FROM achievement_references ar
+		JOIN students s ON ar.student_id = s.id
+		LEFT JOIN lecturers l ON s.advisor_id = l.id
+		WHERE ar.id = $1 AND ar.deleted_at IS NULL
+	`
+
+	var res models.PolicyResource
+	err := r.db.QueryRowContext(ctx, query, achievementID).Scan(&res.StudentID, &res.AdvisorID, &res.Department)
+	if err != nil {
+		return models.PolicyResource{}, err
+	}
+	return res, nil
+}
+
+func (r *policyRepository) GetStudentResource(ctx context.Context, studentID string) (models.PolicyResource, error) {
+	query := `
+		SELECT
+			s.id::text,
+			COALESCE(s.advisor_id::text, ''),
+			COALESCE(l.department, '')
+		FROM students s
+		LEFT JOIN lecturers l ON s.advisor_id = l.id
+		WHERE s.id = $1
+	`
+
+	var res models.PolicyResource
+	err := r.db.QueryRowContext(ctx, query, studentID).Scan(&res.StudentID, &res.AdvisorID, &res.Department)
+	if err != nil {
+		return models.PolicyResource{}, err
+	}
+	return res, nil
+}
+
+func (r *policyRepository) GetLecturerResource(ctx context.Context, lecturerID string) (models.PolicyResource, error) {
+	query := `SELECT l.id::text, COALESCE(l.department, '') FROM lecturers l WHERE l.id = $1`
+
+	var res models.PolicyResource
+	err := r.db.QueryRowContext(ctx, query, lecturerID).Scan(&res.LecturerID, &res.Department)
+	if err != nil {
+		return models.PolicyResource{}, err
+	}
+	// Dosen dianggap "advisor" bagi dirinya sendiri, sehingga scope advisees juga berlaku
+	res.AdvisorID = res.LecturerID
+	return res, nil
+}
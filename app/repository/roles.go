@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"uas/app/apperror"
+	"uas/app/models"
+
+	"github.com/google/uuid"
+)
+
+type RoleRepository interface {
+	GetAllRoles(ctx context.Context) ([]models.Role, error)
+	GetRoleByID(ctx context.Context, id string) (models.Role, error)
+	CreateRole(ctx context.Context, role models.Role) error
+	UpdateRole(ctx context.Context, id string, name string, description string) error
+	DeleteRole(ctx context.Context, id string) error
+	CountUsersByRole(ctx context.Context, roleID string) (int, error)
+	GetAllPermissions(ctx context.Context) ([]models.Permission, error)
+	GetPermissionByID(ctx context.Context, id string) (models.Permission, error)
+	GetPermissionsByRoleID(ctx context.Context, roleID string) ([]models.Permission, error)
+	GrantPermission(ctx context.Context, roleID string, permissionID string, scope string) error
+	RevokePermission(ctx context.Context, roleID string, permissionID string) error
+	CountUsersWithPermission(ctx context.Context, permissionName string, excludeRoleID string, excludeUserID string) (int, error)
+}
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), created_at
+		FROM roles
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []models.Role
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt); err != nil {
+			return nil, fmt.Errorf("gagal scanning row role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetRoleByID(ctx context.Context, id string) (models.Role, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), created_at
+		FROM roles
+		WHERE id = $1
+	`
+
+	var role models.Role
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	if err != nil {
+		return models.Role{}, errRoleNotFound(err)
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) CreateRole(ctx context.Context, role models.Role) error {
+	query := `
+		INSERT INTO roles (id, name, description, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, role.ID, role.Name, role.Description, role.CreatedAt)
+	if err != nil {
+		return roleWriteError("gagal insert role", err)
+	}
+	return nil
+}
+
+func (r *roleRepository) UpdateRole(ctx context.Context, id string, name string, description string) error {
+	query := `UPDATE roles SET name = $1, description = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, name, description, id)
+	if err != nil {
+		return roleWriteError("gagal update role", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errRoleNotFound(sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) DeleteRole(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM roles WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("gagal hapus role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errRoleNotFound(sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) CountUsersByRole(ctx context.Context, roleID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT count(1) FROM users WHERE role_id = $1`, roleID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *roleRepository) GetAllPermissions(ctx context.Context) ([]models.Permission, error) {
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, '')
+		FROM permissions
+		ORDER BY resource ASC, action ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query permissions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+func (r *roleRepository) GetPermissionByID(ctx context.Context, id string) (models.Permission, error) {
+	query := `
+		SELECT id, name, resource, action, COALESCE(description, '')
+		FROM permissions
+		WHERE id = $1
+	`
+
+	var p models.Permission
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description)
+	if err != nil {
+		return models.Permission{}, errPermissionNotFound(err)
+	}
+	return p, nil
+}
+
+func (r *roleRepository) GetPermissionsByRoleID(ctx context.Context, roleID string) ([]models.Permission, error) {
+	query := `
+		SELECT p.id, p.name, p.resource, p.action, COALESCE(p.description, ''), rp.scope
+		FROM role_permissions rp
+		JOIN permissions p ON rp.permission_id = p.id
+		WHERE rp.role_id = $1
+		ORDER BY p.resource ASC, p.action ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query permission role: %w", err)
+	}
+	defer rows.Close()
+
+	var permissions []models.Permission
+	for rows.Next() {
+		var p models.Permission
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description, &p.Scope); err != nil {
+			return nil, fmt.Errorf("gagal scanning row permission: %w", err)
+		}
+		permissions = append(permissions, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// GrantPermission menambahkan permission ke role, atau mengganti scope jika sudah dimiliki
+func (r *roleRepository) GrantPermission(ctx context.Context, roleID string, permissionID string, scope string) error {
+	query := `
+		INSERT INTO role_permissions (role_id, permission_id, scope)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (role_id, permission_id) DO UPDATE SET scope = EXCLUDED.scope
+	`
+
+	_, err := r.db.ExecContext(ctx, query, roleID, permissionID, scope)
+	if err != nil {
+		return fmt.Errorf("gagal grant permission: %w", err)
+	}
+	return nil
+}
+
+func (r *roleRepository) RevokePermission(ctx context.Context, roleID string, permissionID string) error {
+	query := `DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, roleID, permissionID)
+	if err != nil {
+		return fmt.Errorf("gagal revoke permission: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return apperror.NotFound(apperror.CodeRolePermissionNotFound)
+	}
+
+	return nil
+}
+
+// CountUsersWithPermission menghitung user aktif yang memiliki permission tertentu dengan scope all
+// (satu-satunya scope yang diterima route tanpa resolver resource, termasuk seluruh route users:*/roles:*),
+// tanpa menghitung user dengan role excludeRoleID atau user excludeUserID (kosongkan jika tidak dipakai).
+func (r *roleRepository) CountUsersWithPermission(ctx context.Context, permissionName string, excludeRoleID string, excludeUserID string) (int, error) {
+	query := `
+		SELECT count(DISTINCT u.id)
+		FROM users u
+		JOIN role_permissions rp ON u.role_id = rp.role_id
+		JOIN permissions p ON rp.permission_id = p.id
+		WHERE p.name = $1
+		  AND rp.scope = 'all'
+		  AND u.is_active = TRUE
+		  AND u.role_id <> $2
+		  AND u.id <> $3
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, permissionName, orNilUUID(excludeRoleID), orNilUUID(excludeUserID)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func scanPermissions(rows *sql.Rows) ([]models.Permission, error) {
+	var permissions []models.Permission
+	for rows.Next() {
+		var p models.Permission
+		if err := rows.Scan(&p.ID, &p.Name, &p.Resource, &p.Action, &p.Description); err != nil {
+			return nil, fmt.Errorf("gagal scanning row permission: %w", err)
+		}
+		permissions = append(permissions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// roleWriteError memetakan nama role yang duplikat menjadi conflict
+func roleWriteError(msg string, err error) error {
+	if apperror.IsUniqueViolation(err) {
+		return apperror.Wrap(err, apperror.Conflict(apperror.CodeRoleNameTaken))
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+func orNilUUID(id string) string {
+	if id == "" {
+		return uuid.Nil.String()
+	}
+	return id
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"uas/app/models"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session models.Session) error
+	GetActiveSession(ctx context.Context, id string) (models.Session, error)
+	GetActiveSessionsByUserID(ctx context.Context, userID string) ([]models.Session, error)
+	TouchSession(ctx context.Context, id string) error
+	RevokeSession(ctx context.Context, id string, userID string, revokedBy string) error
+	RevokeSessionsByUserID(ctx context.Context, userID string, revokedBy string) ([]string, error)
+	GetRevokedSessionIDsSince(ctx context.Context, since time.Time) ([]string, error)
+}
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) CreateSession(ctx context.Context, session models.Session) error {
+	query := `
+		INSERT INTO user_sessions (
+			id, user_id, device, ip, user_agent, created_at, last_seen_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.Device,
+		session.IP,
+		session.UserAgent,
+		session.CreatedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("gagal insert sesi: %w", err)
+	}
+	return nil
+}
+
+// GetActiveSession mengambil sesi yang belum dicabut dan belum expired
+func (r *sessionRepository) GetActiveSession(ctx context.Context, id string) (models.Session, error) {
+	query := `
+		SELECT id, user_id, COALESCE(device, ''), COALESCE(ip, ''), COALESCE(user_agent, ''),
+			created_at, last_seen_at, expires_at
+		FROM user_sessions
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	`
+
+	var s models.Session
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&s.ID, &s.UserID, &s.Device, &s.IP, &s.UserAgent,
+		&s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt,
+	)
+	if err != nil {
+		return models.Session{}, errSessionNotFound(err)
+	}
+	return s, nil
+}
+
+func (r *sessionRepository) GetActiveSessionsByUserID(ctx context.Context, userID string) ([]models.Session, error) {
+	query := `
+		SELECT id, user_id, COALESCE(device, ''), COALESCE(ip, ''), COALESCE(user_agent, ''),
+			created_at, last_seen_at, expires_at
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query sesi: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var s models.Session
+		err := rows.Scan(
+			&s.ID, &s.UserID, &s.Device, &s.IP, &s.UserAgent,
+			&s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scanning row sesi: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) TouchSession(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE user_sessions SET last_seen_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("gagal update last seen sesi: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession mencabut satu sesi milik userID, error not found jika tidak ditemukan
+func (r *sessionRepository) RevokeSession(ctx context.Context, id string, userID string, revokedBy string) error {
+	query := `
+		UPDATE user_sessions
+		SET revoked_at = NOW(), revoked_by = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID, revokedBy)
+	if err != nil {
+		return fmt.Errorf("gagal mencabut sesi: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errSessionNotFound(sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// RevokeSessionsByUserID mencabut semua sesi aktif user dan mengembalikan ID sesi yang dicabut
+func (r *sessionRepository) RevokeSessionsByUserID(ctx context.Context, userID string, revokedBy string) ([]string, error) {
+	query := `
+		UPDATE user_sessions
+		SET revoked_at = NOW(), revoked_by = $2
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, revokedBy)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencabut sesi user: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+func (r *sessionRepository) GetRevokedSessionIDsSince(ctx context.Context, since time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM user_sessions WHERE revoked_at >= $1`, since)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query sesi yang dicabut: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+func scanIDs(rows *sql.Rows) ([]string, error) {
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("gagal scanning id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	return ids, nil
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"uas/app/apperror"
 	"uas/app/models"
+
+	"github.com/google/uuid"
 )
 
 func CreateStudent (tx *sql.Tx, student models.Student) error {
@@ -21,17 +23,29 @@ func CreateStudent (tx *sql.Tx, student models.Student) error {
 			student.StudentID,
 			student.ProgramStudy,
 			student.AcademicYear,
-			student.AdvisorID,
+			nullableUUID(student.AdvisorID),
 			student.CreatedAt,
     )
 
 		return err
 }
 
+// nullableUUID menyimpan uuid kosong sebagai NULL agar tidak melanggar foreign key
+func nullableUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
 type StudentRepository interface {
-	GetAllStudentsByRole(ctx context.Context, roleName string) ([]models.GetStudent, error)
+	ListStudents(ctx context.Context, filter models.StudentFilter) ([]models.GetStudent, models.ListMeta, error)
+	ExportStudents(ctx context.Context, filter models.StudentFilter, fn func(models.GetStudent) error) error
 	GetStudentByID(ctx context.Context, id string) (models.GetStudent, error)
 	UpdateStudentAdvisor(ctx context.Context, studentID string, advisorID string) error
+	UpdateStudent(ctx context.Context, id string, req models.UpdateStudentRequest) error
+	UpdateStudentStatus(ctx context.Context, id string, status string, reason string, changedBy string) (string, error)
+	GetStudentStatusHistory(ctx context.Context, id string) ([]models.StudentStatusHistory, error)
 }
 
 type studentRepository struct {
@@ -42,59 +56,111 @@ func NewStudentRepository(db *sql.DB) StudentRepository {
 	return &studentRepository{db: db}
 }
 
-func (r *studentRepository) GetAllStudentsByRole(ctx context.Context, roleName string) ([]models.GetStudent, error) {
-	query := `
-		SELECT 
+// ListStudents mengambil daftar mahasiswa dengan filter, pencarian (nama, NIM, email), sorting dan pagination
+func (r *studentRepository) ListStudents(ctx context.Context, filter models.StudentFilter) ([]models.GetStudent, models.ListMeta, error) {
+	q := studentListQuery(filter)
+
+	students := []models.GetStudent{}
+	meta, err := q.run(ctx, r.db, studentListColumns, filter.ListParams,
+		func(rows *sql.Rows, sortValue *string) (string, error) {
+			s, err := scanListStudent(rows, sortValue)
+			students = append(students, s)
+			return s.ID, err
+		})
+	if err != nil {
+		return nil, meta, fmt.Errorf("gagal mengambil daftar mahasiswa: %w", err)
+	}
+
+	return students, meta, nil
+}
+
+// ExportStudents mengalirkan seluruh mahasiswa beserta dosen walinya sesuai filter ListStudents (tanpa pagination) ke fn
+func (r *studentRepository) ExportStudents(ctx context.Context, filter models.StudentFilter, fn func(models.GetStudent) error) error {
+	q := studentListQuery(filter)
+
+	return q.stream(ctx, r.db, studentListColumns, filter.ListParams, func(rows *sql.Rows) error {
+		s, err := scanListStudent(rows)
+		if err != nil {
+			return fmt.Errorf("gagal scanning row: %w", err)
+		}
+		return fn(s)
+	})
+}
+
+const studentListColumns = `
 					s.id, 
 					s.user_id, 
 					s.student_id, 
-					s.program_study, 
-					s.academy_year,
+					COALESCE(s.program_study, ''), 
+					COALESCE(s.academy_year, ''),
+					COALESCE(s.advisor_id::text, ''),
+					COALESCE(al.lecturer_id, ''),
+					COALESCE(au.full_name, ''),
+					s.status,
 					u.full_name, 
 					u.username, 
 					u.email,
 					u.is_active,
-					r.name as role_name -- Ambil nama role
-				FROM students s
+					r.name`
+
+func studentListQuery(filter models.StudentFilter) listQuery {
+	q := listQuery{
+		from: `FROM students s
 				JOIN users u ON s.user_id = u.id
 				JOIN roles r ON u.role_id = r.id
-				WHERE r.name = $1
-				ORDER BY u.full_name ASC
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, roleName)
-	if err != nil {
-		return nil, fmt.Errorf("gagal query students join roles: %w", err)
+				LEFT JOIN lecturers al ON s.advisor_id = al.id
+				LEFT JOIN users au ON al.user_id = au.id`,
+		idColumn: "s.id",
+		sortColumn: map[string]string{
+			"full_name":     "u.full_name",
+			"nim":           "s.student_id",
+			"program_study": "COALESCE(s.program_study, '')",
+			"academy_year":  "COALESCE(s.academy_year, '')",
+			"created_at":    "s.created_at",
+		},
 	}
-	defer rows.Close()
 
-	var students []models.GetStudent
-
-	for rows.Next() {
-		var s models.GetStudent
-		err := rows.Scan(
-			&s.ID,
-			&s.UserID,
-			&s.NIM,
-			&s.ProgramStudy,
-			&s.AcademyYear,
-			&s.FullName,
-			&s.Username,
-			&s.Email,
-			&s.IsActive,
-			&s.RoleName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("gagal scanning row: %w", err)
-		}
-		students = append(students, s)
+	q.where("r.name = $%d", models.RoleMahasiswa)
+	q.search(filter.Search, "u.full_name", "s.student_id", "u.email")
+	if filter.ProgramStudy != "" {
+		q.where("s.program_study = $%d", filter.ProgramStudy)
 	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	if filter.AcademicYear != "" {
+		q.where("s.academy_year = $%d", filter.AcademicYear)
+	}
+	if filter.AdvisorID != "" {
+		q.where("s.advisor_id = $%d", filter.AdvisorID)
+	}
+	if filter.Status != "" {
+		q.where("s.status = $%d", filter.Status)
+	}
+	if filter.IsActive != nil {
+		q.where("u.is_active = $%d", *filter.IsActive)
 	}
+	return q
+}
 
-	return students, nil
+// scanListStudent membaca kolom studentListColumns; extra untuk kolom tambahan (nilai sort)
+func scanListStudent(rows *sql.Rows, extra ...interface{}) (models.GetStudent, error) {
+	var s models.GetStudent
+	dest := []interface{}{
+		&s.ID,
+		&s.UserID,
+		&s.NIM,
+		&s.ProgramStudy,
+		&s.AcademyYear,
+		&s.AdvisorID,
+		&s.AdvisorNIP,
+		&s.AdvisorName,
+		&s.Status,
+		&s.FullName,
+		&s.Username,
+		&s.Email,
+		&s.IsActive,
+		&s.RoleName,
+	}
+	err := rows.Scan(append(dest, extra...)...)
+	return s, err
 }
 
 func (r *studentRepository) GetStudentByID(ctx context.Context, id string) (models.GetStudent, error) {
@@ -105,6 +171,10 @@ func (r *studentRepository) GetStudentByID(ctx context.Context, id string) (mode
 			s.student_id, 
 			s.program_study, 
 			s.academy_year,
+			COALESCE(s.advisor_id::text, ''),
+			COALESCE(al.lecturer_id, ''),
+			COALESCE(au.full_name, ''),
+			s.status,
 			u.full_name, 
 			u.username, 
 			u.email,
@@ -113,6 +183,8 @@ func (r *studentRepository) GetStudentByID(ctx context.Context, id string) (mode
 		FROM students s
 		JOIN users u ON s.user_id = u.id
 		JOIN roles r ON u.role_id = r.id
+		LEFT JOIN lecturers al ON s.advisor_id = al.id
+		LEFT JOIN users au ON al.user_id = au.id
 		WHERE s.id = $1
 	`
 
@@ -124,6 +196,10 @@ func (r *studentRepository) GetStudentByID(ctx context.Context, id string) (mode
 		&s.NIM,
 		&s.ProgramStudy,
 		&s.AcademyYear,
+		&s.AdvisorID,
+		&s.AdvisorNIP,
+		&s.AdvisorName,
+		&s.Status,
 		&s.FullName,
 		&s.Username,
 		&s.Email,
@@ -132,7 +208,7 @@ func (r *studentRepository) GetStudentByID(ctx context.Context, id string) (mode
 	)
 
 	if err != nil {
-		return models.GetStudent{}, err
+		return models.GetStudent{}, errStudentNotFound(err)
 	}
 
 	return s, nil
@@ -147,6 +223,10 @@ func (r *studentRepository) UpdateStudentAdvisor(ctx context.Context, studentID
 
 	result, err := r.db.ExecContext(ctx, query, advisorID, studentID)
 	if err != nil {
+		// advisor_id tidak ada di tabel lecturers
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.Wrap(err, apperror.NotFound(apperror.CodeAdvisorNotFound))
+		}
 		return fmt.Errorf("gagal update dosen wali: %w", err)
 	}
 
@@ -155,8 +235,115 @@ func (r *studentRepository) UpdateStudentAdvisor(ctx context.Context, studentID
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("student_not_found")
+		return errStudentNotFound(sql.ErrNoRows)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// studentConstraintError memetakan NIM yang sudah dipakai mahasiswa lain ke STUDENT_NIM_TAKEN
+func studentConstraintError(err error) error {
+	if apperror.IsUniqueViolation(err) && apperror.ViolatedConstraint(err) == "students_student_id_key" {
+		return apperror.Wrap(err, apperror.Conflict(apperror.CodeStudentNIMTaken))
+	}
+	return err
+}
+
+// UpdateStudent mengoreksi NIM, program studi dan angkatan mahasiswa
+func (r *studentRepository) UpdateStudent(ctx context.Context, id string, req models.UpdateStudentRequest) error {
+	query := `
+		UPDATE students
+		SET student_id = $1, program_study = $2, academy_year = NULLIF($3, ''), updated_at = NOW()
+		WHERE id = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, req.StudentID, req.ProgramStudy, req.AcademicYear, id)
+	if err != nil {
+		return fmt.Errorf("gagal update data mahasiswa: %w", studentConstraintError(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errStudentNotFound(sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// UpdateStudentStatus mengubah status studi dan mencatat riwayatnya dalam satu transaksi, lalu mengembalikan
+// status sebelumnya. Baris mahasiswa dikunci agar dua perubahan bersamaan tidak melewati aturan transisi.
+func (r *studentRepository) UpdateStudentStatus(ctx context.Context, id string, status string, reason string, changedBy string) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("gagal memulai transaksi database: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM students WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err != nil {
+		return "", errStudentNotFound(err)
+	}
+
+	if !models.CanTransitionStudentStatus(current, status) {
+		return current, apperror.Conflict(apperror.CodeStudentStatusTransition).With("from", current).With("to", status)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE students SET status = $1, updated_at = NOW() WHERE id = $2`, status, id)
+	if err != nil {
+		return current, fmt.Errorf("gagal update status mahasiswa: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO student_status_history (student_id, from_status, to_status, reason, changed_by)
+		VALUES ($1, $2, $3, $4, $5)`,
+		id, current, status, reason, changedBy)
+	if err != nil {
+		return current, fmt.Errorf("gagal menyimpan riwayat status mahasiswa: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return current, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return current, nil
+}
+
+// GetStudentStatusHistory mengambil riwayat status mahasiswa, terbaru lebih dulu
+func (r *studentRepository) GetStudentStatusHistory(ctx context.Context, id string) ([]models.StudentStatusHistory, error) {
+	query := `
+		SELECT
+			h.id,
+			h.student_id,
+			h.from_status,
+			h.to_status,
+			h.reason,
+			COALESCE(h.changed_by::text, ''),
+			COALESCE(u.full_name, ''),
+			h.created_at
+		FROM student_status_history h
+		LEFT JOIN users u ON h.changed_by = u.id
+		WHERE h.student_id = $1
+		ORDER BY h.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat status mahasiswa: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.StudentStatusHistory{}
+	for rows.Next() {
+		var h models.StudentStatusHistory
+		if err := rows.Scan(&h.ID, &h.StudentID, &h.FromStatus, &h.ToStatus, &h.Reason, &h.ChangedBy, &h.ChangedByName, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("gagal scanning row: %w", err)
+		}
+		history = append(history, h)
+	}
+
+	return history, rows.Err()
+}
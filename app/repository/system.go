@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"uas/app/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SystemRepository interface {
+	PingPostgres(ctx context.Context) error
+	PingMongo(ctx context.Context) error
+	GetMigrationVersion(ctx context.Context) (models.MigrationStatus, error)
+	GetPostgresStats() sql.DBStats
+	GetMongoServerInfo(ctx context.Context) (models.MongoServerInfo, error)
+}
+
+type systemRepository struct {
+	pg    *sql.DB
+	mongo *mongo.Database
+}
+
+func NewSystemRepository(pg *sql.DB, mongo *mongo.Database) SystemRepository {
+	return &systemRepository{pg: pg, mongo: mongo}
+}
+
+func (r *systemRepository) PingPostgres(ctx context.Context) error {
+	return r.pg.PingContext(ctx)
+}
+
+func (r *systemRepository) PingMongo(ctx context.Context) error {
+	return r.mongo.Client().Ping(ctx, nil)
+}
+
+// GetMigrationVersion membaca tabel schema_migrations milik golang-migrate,
+// sql.ErrNoRows jika belum ada migration yang dijalankan
+func (r *systemRepository) GetMigrationVersion(ctx context.Context) (models.MigrationStatus, error) {
+	var status models.MigrationStatus
+	err := r.pg.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&status.Version, &status.Dirty)
+	if err != nil {
+		return models.MigrationStatus{}, err
+	}
+	return status, nil
+}
+
+func (r *systemRepository) GetPostgresStats() sql.DBStats {
+	return r.pg.Stats()
+}
+
+func (r *systemRepository) GetMongoServerInfo(ctx context.Context) (models.MongoServerInfo, error) {
+	var result struct {
+		Version    string `bson:"version"`
+		GitVersion string `bson:"gitVersion"`
+	}
+
+	err := r.mongo.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&result)
+	if err != nil {
+		return models.MongoServerInfo{}, fmt.Errorf("gagal membaca buildInfo MongoDB: %w", err)
+	}
+
+	return models.MongoServerInfo{
+		Version:    result.Version,
+		GitVersion: result.GitVersion,
+		Database:   r.mongo.Name(),
+	}, nil
+}
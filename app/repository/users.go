@@ -1,83 +1,299 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
+	"uas/app/apperror"
 	"uas/app/models"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func GetAllUsers(db *sql.DB) ([]models.User, error){
-	var users []models.User
+type UserRepository interface {
+	Login(ctx context.Context, loginInput string) (models.User, error)
+	ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, models.ListMeta, error)
+	ExportUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error
+	GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error)
+	CreateUser(ctx context.Context, user models.User, student *models.Student, lecture *models.Lecture) error
+	UpdateUser(ctx context.Context, id uuid.UUID, user models.UpdateUser) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	SoftDeleteUser(ctx context.Context, id uuid.UUID) error
+	RestoreUser(ctx context.Context, id uuid.UUID) error
+	AnonymizeUser(ctx context.Context, id uuid.UUID, entry models.AuditLog) error
+	GetUserDependencies(ctx context.Context, id uuid.UUID) (models.UserDependencies, error)
+	UpdateUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error
+	UpdatePreferredLanguage(ctx context.Context, userID uuid.UUID, lang string) error
+	FindExistingValues(ctx context.Context, field string, values []string) (map[string]bool, error)
+	ImportUsers(ctx context.Context, batch []models.ImportUser) ([]error, error)
+}
 
-	query := `
-		SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, r.name, u.is_active, u.created_at, u.updated_at
-		FROM users u
-		JOIN roles r ON u.role_id = r.id
-	`
+type userRepository struct {
+	db    *sql.DB
+	mongo *mongo.Database
+}
+
+func NewUserRepository(db *sql.DB, mongo *mongo.Database) UserRepository {
+	return &userRepository{db: db, mongo: mongo}
+}
 
-	rows, err := db.Query(query)
-	if err != nil{
-		return nil, err
+// ListUsers mengambil daftar user dengan filter, pencarian, sorting dan pagination
+func (r *userRepository) ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, models.ListMeta, error) {
+	q := userListQuery(filter)
+
+	users := []models.User{}
+	meta, err := q.run(ctx, r.db, userListColumns, filter.ListParams,
+		func(rows *sql.Rows, sortValue *string) (string, error) {
+			user, err := scanListUser(rows, sortValue)
+			users = append(users, user)
+			return user.ID.String(), err
+		})
+	if err != nil {
+		return nil, meta, fmt.Errorf("gagal mengambil daftar user: %w", err)
 	}
 
-	defer rows.Close()
+	return users, meta, nil
+}
 
-	for rows.Next(){
-		var user models.User
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.Email,
-			&user.PasswordHash,
-			&user.FullName,
-			&user.RoleID,
-			&user.RoleName,
-			&user.IsActive,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+// ExportUsers mengalirkan seluruh user sesuai filter ListUsers (tanpa pagination) ke fn
+func (r *userRepository) ExportUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error {
+	q := userListQuery(filter)
 
+	return q.stream(ctx, r.db, userListColumns, filter.ListParams, func(rows *sql.Rows) error {
+		user, err := scanListUser(rows)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("gagal scanning row: %w", err)
 		}
+		return fn(user)
+	})
+}
 
-		users = append(users, user)
+const userListColumns = "u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, r.name, u.is_active, u.created_at, u.updated_at, u.deleted_at, u.anonymized_at"
+
+func userListQuery(filter models.UserFilter) listQuery {
+	q := listQuery{
+		from:     "FROM users u JOIN roles r ON u.role_id = r.id",
+		idColumn: "u.id",
+		sortColumn: map[string]string{
+			"full_name":  "u.full_name",
+			"username":   "u.username",
+			"email":      "u.email",
+			"role_name":  "r.name",
+			"created_at": "u.created_at",
+		},
 	}
 
-	return users, err
+	// User yang sudah di-soft delete hanya tampil jika diminta dengan ?include_deleted=true
+	if !filter.IncludeDeleted {
+		q.conditions = append(q.conditions, "u.deleted_at IS NULL")
+	}
+	q.search(filter.Search, "u.full_name", "u.username", "u.email")
+	if filter.RoleID != "" {
+		q.where("u.role_id = $%d", filter.RoleID)
+	}
+	if filter.RoleName != "" {
+		q.where("r.name = $%d", filter.RoleName)
+	}
+	if filter.IsActive != nil {
+		q.where("u.is_active = $%d", *filter.IsActive)
+	}
+	return q
 }
 
-func GetUserByID(db *sql.DB, id uuid.UUID) (models.User, error) {
+// scanListUser membaca kolom userListColumns; extra untuk kolom tambahan (nilai sort)
+func scanListUser(rows *sql.Rows, extra ...interface{}) (models.User, error) {
+	var user models.User
+	dest := []interface{}{
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.PasswordHash,
+		&user.FullName,
+		&user.RoleID,
+		&user.RoleName,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.AnonymizedAt,
+	}
+	err := rows.Scan(append(dest, extra...)...)
+	return user, err
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
 	var user models.User
 
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
-		FROM users
-		WHERE id = $1
+		SELECT u.id, u.username, u.email, u.password_hash, u.full_name, u.role_id, COALESCE(r.name, ''), u.is_active,
+			COALESCE(u.preferred_language, ''), u.created_at, u.updated_at, u.deleted_at, u.anonymized_at
+		FROM users u
+		LEFT JOIN roles r ON u.role_id = r.id
+		WHERE u.id = $1
 	`
 
-	err := db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
 		&user.FullName,
 		&user.RoleID,
+		&user.RoleName,
 		&user.IsActive,
+		&user.PreferredLanguage,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.AnonymizedAt,
 	)
 
 	if err != nil {
-		return user, err
+		return user, errUserNotFound(err)
 	}
 
 	return user, nil
 }
 
-func CreateUser(tx *sql.Tx, user models.User) error {
+// CreateUser menyimpan user beserta profil mahasiswa/dosen (jika ada) dalam satu transaksi
+func (r *userRepository) CreateUser(ctx context.Context, user models.User, student *models.Student, lecture *models.Lecture) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi database: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertUser(tx, user); err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return apperror.Wrap(err, apperror.Conflict(apperror.CodeUserAlreadyExists))
+		}
+		return fmt.Errorf("gagal menyimpan data user: %w", err)
+	}
+
+	if student != nil {
+		if err := CreateStudent(tx, *student); err != nil {
+			return fmt.Errorf("gagal menyimpan data mahasiswa: %w", studentConstraintError(err))
+		}
+	}
+
+	if lecture != nil {
+		if err := CreateLecture(tx, *lecture); err != nil {
+			return fmt.Errorf("gagal menyimpan data dosen: %w", lecturerConstraintError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return nil
+}
+
+// Query pengecekan nilai unik per kolom file import
+var existingValueQueries = map[string]string{
+	"username":    `SELECT username FROM users WHERE username = ANY($1)`,
+	"email":       `SELECT email FROM users WHERE LOWER(email) = ANY($1)`,
+	"student_id":  `SELECT student_id FROM students WHERE student_id = ANY($1)`,
+	"lecturer_id": `SELECT lecturer_id FROM lecturers WHERE lecturer_id = ANY($1)`,
+}
+
+// FindExistingValues mengembalikan nilai yang sudah terpakai pada kolom unik (username, email, student_id, lecturer_id).
+// Email dibandingkan dalam huruf kecil, sehingga values untuk email harus sudah di-lowercase.
+func (r *userRepository) FindExistingValues(ctx context.Context, field string, values []string) (map[string]bool, error) {
+	query, ok := existingValueQueries[field]
+	if !ok {
+		return nil, fmt.Errorf("kolom %q tidak dapat dicek", field)
+	}
+
+	existing := map[string]bool{}
+	if len(values) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(values))
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengecek %s yang sudah terdaftar: %w", field, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("gagal membaca %s: %w", field, err)
+		}
+		if field == "email" {
+			value = strings.ToLower(value)
+		}
+		existing[value] = true
+	}
+	return existing, rows.Err()
+}
+
+// ImportUsers menyimpan satu batch user beserta profilnya dalam satu transaksi.
+// Setiap baris memakai savepoint sehingga baris yang gagal tidak membatalkan baris lain;
+// error per baris dikembalikan sesuai urutan batch (nil jika berhasil).
+func (r *userRepository) ImportUsers(ctx context.Context, batch []models.ImportUser) ([]error, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memulai transaksi database: %w", err)
+	}
+	defer tx.Rollback()
+
+	rowErrs := make([]error, len(batch))
+	for i, item := range batch {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT import_row`); err != nil {
+			return nil, fmt.Errorf("gagal membuat savepoint: %w", err)
+		}
+
+		if err := insertImportUser(tx, item); err != nil {
+			rowErrs[i] = err
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_row`); err != nil {
+				return nil, fmt.Errorf("gagal rollback savepoint: %w", err)
+			}
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT import_row`); err != nil {
+			return nil, fmt.Errorf("gagal melepas savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+	return rowErrs, nil
+}
+
+func insertImportUser(tx *sql.Tx, item models.ImportUser) error {
+	if err := insertUser(tx, item.User); err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return apperror.Wrap(err, apperror.Conflict(apperror.CodeUserAlreadyExists))
+		}
+		return fmt.Errorf("gagal menyimpan data user: %w", err)
+	}
+
+	if item.Student != nil {
+		if err := CreateStudent(tx, *item.Student); err != nil {
+			return fmt.Errorf("gagal menyimpan data mahasiswa: %w", apperror.FromDatabase(err))
+		}
+	}
+
+	if item.Lecture != nil {
+		if err := CreateLecture(tx, *item.Lecture); err != nil {
+			return fmt.Errorf("gagal menyimpan data dosen: %w", lecturerConstraintError(err))
+		}
+	}
+	return nil
+}
+
+func insertUser(tx *sql.Tx, user models.User) error {
 	query := `
 		INSERT INTO users (
 			id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
@@ -100,24 +316,22 @@ func CreateUser(tx *sql.Tx, user models.User) error {
 	return err
 }
 
-func UpdateUser(db *sql.DB, id uuid.UUID, user models.UpdateUser) error {
+func (r *userRepository) UpdateUser(ctx context.Context, id uuid.UUID, user models.UpdateUser) error {
 	query := `
 		UPDATE users 
 		SET 
 			username = $1, 
 			email = $2, 
 			full_name = $3, 
-			role_id = $4, 
-			is_active = $5, 
-			updated_at = $6 
-		WHERE id = $7
+			is_active = $4, 
+			updated_at = $5 
+		WHERE id = $6
 	`
 
-	result, err := db.Exec(query,
+	result, err := r.db.ExecContext(ctx, query,
 		user.Username,
 		user.Email,
 		user.FullName,
-		user.RoleID,
 		user.IsActive,
 		time.Now(),
 		id,
@@ -133,40 +347,277 @@ func UpdateUser(db *sql.DB, id uuid.UUID, user models.UpdateUser) error {
 	}
 
 	if rowsAffected == 0 {
-		return sql.ErrNoRows 
+		return errUserNotFound(sql.ErrNoRows)
 	}
 
 	return nil
 }
 
-func DeleteUser(db *sql.DB, id uuid.UUID) error {
-	query := "DELETE FROM users WHERE id = $1"
+// DeleteUser menghapus user secara permanen beserta profil mahasiswa/dosen dan sesinya. Penghapusan ditolak
+// dengan USER_HAS_DEPENDENCIES selama masih ada prestasi, mahasiswa bimbingan atau riwayat verifikasi.
+func (r *userRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi database: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := db.Exec(query, id)
+	deps, err := lockUserDependencies(ctx, tx, id)
 	if err != nil {
 		return err
 	}
+	if deps.Blocking() {
+		return errUserHasDependencies(deps)
+	}
+
+	// Prestasi draft yang sudah di-soft delete tidak menghalangi, tetapi harus dihapus agar foreign key terpenuhi
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM achievement_references
+		WHERE deleted_at IS NOT NULL AND student_id IN (SELECT id FROM students WHERE user_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus prestasi terhapus milik user: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id); err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.Wrap(err, errUserHasDependencies(deps))
+		}
+		return fmt.Errorf("gagal menghapus user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return nil
+}
+
+func errUserHasDependencies(deps models.UserDependencies) *apperror.Error {
+	return apperror.Conflict(apperror.CodeUserHasDependencies).
+		With("achievements", deps.Achievements).
+		With("advisees", deps.Advisees).
+		With("verifications", deps.Verifications)
+}
+
+const userDependenciesQuery = `
+	SELECT
+		(SELECT count(1) FROM achievement_references ar JOIN students s ON ar.student_id = s.id
+			WHERE s.user_id = $1 AND ar.deleted_at IS NULL),
+		(SELECT count(1) FROM students s JOIN lecturers l ON s.advisor_id = l.id WHERE l.user_id = $1),
+		(SELECT count(1) FROM achievement_references WHERE verified_by = $1)
+`
+
+// GetUserDependencies menghitung data yang masih merujuk user (lihat models.UserDependencies)
+func (r *userRepository) GetUserDependencies(ctx context.Context, id uuid.UUID) (models.UserDependencies, error) {
+	var deps models.UserDependencies
+	err := r.db.QueryRowContext(ctx, userDependenciesQuery, id).Scan(&deps.Achievements, &deps.Advisees, &deps.Verifications)
+	if err != nil {
+		return deps, fmt.Errorf("gagal menghitung data terkait user: %w", err)
+	}
+	return deps, nil
+}
+
+// lockUserDependencies mengunci baris user lalu menghitung data terkait dalam transaksi yang sama,
+// sehingga tidak ada prestasi atau bimbingan baru yang lolos di antara pengecekan dan penghapusan
+func lockUserDependencies(ctx context.Context, tx *sql.Tx, id uuid.UUID) (models.UserDependencies, error) {
+	var deps models.UserDependencies
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE", id).Scan(new(uuid.UUID)); err != nil {
+		return deps, errUserNotFound(err)
+	}
+
+	err := tx.QueryRowContext(ctx, userDependenciesQuery, id).Scan(&deps.Achievements, &deps.Advisees, &deps.Verifications)
+	if err != nil {
+		return deps, fmt.Errorf("gagal menghitung data terkait user: %w", err)
+	}
+	return deps, nil
+}
+
+// SoftDeleteUser menonaktifkan user dan menandainya terhapus tanpa menghapus data apa pun
+func (r *userRepository) SoftDeleteUser(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET is_active = FALSE, deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus user: %w", err)
+	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rowsAffected == 0 {
+		return apperror.Conflict(apperror.CodeUserDeleted)
+	}
 
+	return nil
+}
+
+// RestoreUser mengaktifkan kembali user yang di-soft delete; user yang sudah dianonimkan tidak dapat dipulihkan
+func (r *userRepository) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET is_active = TRUE, deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("gagal memulihkan user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
 	if rowsAffected == 0 {
-		return sql.ErrNoRows
+		return apperror.Conflict(apperror.CodeUserNotDeleted)
 	}
 
 	return nil
 }
 
-func UpdateUserRole(db *sql.DB, userID uuid.UUID, roleID uuid.UUID) error {
+// AnonymizeUser menghapus data pribadi user secara permanen namun mempertahankan baris user, profil dan
+// prestasi terverifikasi untuk statistik. Username, email, nama, NIM/NIP diganti nilai acak yang tidak dapat
+// ditelusuri, password hash dikosongkan sehingga tidak ada password yang cocok, prestasi yang belum terverifikasi di-soft delete dan jejak
+// IP/perangkat pada sesi dibersihkan. Dosen yang masih memiliki mahasiswa bimbingan ditolak.
+// Dokumen MongoDB prestasi yang belum terverifikasi dikosongkan dan data pribadi pada audit log diredaksi;
+// entry audit user.anonymize ditulis di transaksi yang sama beserta daftar ID audit log yang diredaksi.
+func (r *userRepository) AnonymizeUser(ctx context.Context, id uuid.UUID, entry models.AuditLog) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi database: %w", err)
+	}
+	defer tx.Rollback()
+
+	deps, err := lockUserDependencies(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if deps.Advisees > 0 {
+		return errUserHasDependencies(deps)
+	}
+
+	// Termasuk prestasi yang sudah di-soft delete sebelumnya, karena dokumen MongoDB-nya masih utuh
+	rows, err := tx.QueryContext(ctx, `
+		SELECT mongo_achievement_id FROM achievement_references
+		WHERE status <> 'verified' AND student_id IN (SELECT id FROM students WHERE user_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil prestasi user: %w", err)
+	}
+	var mongoIDs []string
+	for rows.Next() {
+		var mongoID string
+		if err := rows.Scan(&mongoID); err != nil {
+			rows.Close()
+			return fmt.Errorf("gagal scanning prestasi user: %w", err)
+		}
+		mongoIDs = append(mongoIDs, mongoID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	token := strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE users
+			SET username = $2, email = $3, full_name = $4, password_hash = '', preferred_language = NULL,
+				is_active = FALSE, deleted_at = COALESCE(deleted_at, NOW()), anonymized_at = NOW(), updated_at = NOW()
+			WHERE id = $1`,
+			[]interface{}{id, "anon_" + token, "anon_" + token + "@anonymized.invalid", "Pengguna Anonim"}},
+		{`UPDATE students SET student_id = $2, updated_at = NOW() WHERE user_id = $1`, []interface{}{id, "ANON" + token}},
+		{`UPDATE lecturers SET lecturer_id = $2, updated_at = NOW() WHERE user_id = $1`, []interface{}{id, "ANON" + token}},
+		{`UPDATE achievement_references SET deleted_at = NOW(), updated_at = NOW()
+			WHERE deleted_at IS NULL AND status <> 'verified'
+			  AND student_id IN (SELECT id FROM students WHERE user_id = $1)`, []interface{}{id}},
+		{`UPDATE user_sessions SET device = NULL, ip = NULL, user_agent = NULL WHERE user_id = $1`, []interface{}{id}},
+	}
+	for _, st := range statements {
+		if _, err := tx.ExecContext(ctx, st.query, st.args...); err != nil {
+			return fmt.Errorf("gagal menganonimkan user: %w", err)
+		}
+	}
+
+	// MongoDB dibersihkan sebelum commit: jika gagal, transaksi PostgreSQL dibatalkan dan anonimisasi dapat
+	// diulang; jika commit yang gagal, dokumen yang sudah dikosongkan aman untuk dikosongkan ulang
+	if err := r.scrubAchievementDocuments(ctx, mongoIDs); err != nil {
+		return err
+	}
+
+	redacted, err := redactUserAuditTx(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	after := map[string]interface{}{}
+	if len(entry.After) > 0 {
+		if err := json.Unmarshal(entry.After, &after); err != nil {
+			return fmt.Errorf("gagal membaca audit anonimisasi: %w", err)
+		}
+	}
+	after[models.AuditRedactedIDsKey] = redacted
+	if entry.After, err = json.Marshal(after); err != nil {
+		return fmt.Errorf("gagal menyusun audit anonimisasi: %w", err)
+	}
+	if err := appendAuditTx(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return nil
+}
+
+// scrubAchievementDocuments mengosongkan isi dokumen prestasi di MongoDB dan menandainya terhapus.
+// _id, studentId, jenis prestasi dan timestamp dipertahankan agar referensi PostgreSQL tetap konsisten.
+func (r *userRepository) scrubAchievementDocuments(ctx context.Context, mongoIDs []string) error {
+	oids := make([]primitive.ObjectID, 0, len(mongoIDs))
+	for _, id := range mongoIDs {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"title":        "",
+			"description":  "",
+			"details":      bson.M{},
+			"tags":         bson.A{},
+			"attachments":  bson.A{},
+			"anonymizedAt": now,
+			"updatedAt":    now,
+		},
+		// $min mengisi deletedAt jika belum ada tanpa menimpa waktu soft delete sebelumnya
+		"$min": bson.M{"deletedAt": now},
+	}
+	if _, err := r.mongo.Collection("achievements").UpdateMany(ctx, bson.M{"_id": bson.M{"$in": oids}}, update); err != nil {
+		return fmt.Errorf("gagal mengosongkan dokumen prestasi mongo: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error {
     query := `
         UPDATE users 
         SET role_id = $1, updated_at = $2 
         WHERE id = $3
     `
 
-    result, err := db.Exec(query, roleID, time.Now(), userID)
+    result, err := r.db.ExecContext(ctx, query, roleID, time.Now(), userID)
     if err != nil {
         return err
     }
@@ -177,8 +628,29 @@ func UpdateUserRole(db *sql.DB, userID uuid.UUID, roleID uuid.UUID) error {
     }
 
     if rowsAffected == 0 {
-        return sql.ErrNoRows
+        return errUserNotFound(sql.ErrNoRows)
     }
 
     return nil
-}
\ No newline at end of file
+}
+
+// UpdatePreferredLanguage menyimpan bahasa respons pilihan user
+func (r *userRepository) UpdatePreferredLanguage(ctx context.Context, userID uuid.UUID, lang string) error {
+	query := `UPDATE users SET preferred_language = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, lang, time.Now(), userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errUserNotFound(sql.ErrNoRows)
+	}
+
+	return nil
+}
@@ -2,9 +2,12 @@ package services
 
 import (
 	"time"
+	"uas/app/apperror"
 	"uas/app/models"
 	"uas/app/repository"
 	"uas/helpers"
+	"uas/i18n"
+	"uas/metrics"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -21,50 +24,32 @@ type AchievementService interface {
 }
 
 type achievementService struct {
-	repo repository.AchievementRepository
+	repo      repository.AchievementRepository
+	auditRepo repository.AuditRepository
 }
 
-func NewAchievementService(repo repository.AchievementRepository) AchievementService {
-	return &achievementService{repo: repo}
+func NewAchievementService(repo repository.AchievementRepository, auditRepo repository.AuditRepository) AchievementService {
+	return &achievementService{repo: repo, auditRepo: auditRepo}
 }
 
 func (s *achievementService) CreateAchievement(c *fiber.Ctx) error {
 
 	var req models.CreateAchievementRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format data tidak valid",
-			"success": false,
-		})
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
 	}
 
-	userIDLocal := c.Locals("user_id")
-	if userIDLocal == nil {
-		return c.Status(401).JSON(fiber.Map{
-			"message": "Unauthorized: User ID tidak ditemukan",
-			"success": false,
-		})
-	}
-
-	var userID string
-	switch v := userIDLocal.(type) {
-	case string:
-		userID = v
-	case uuid.UUID:
-		userID = v.String()
-	default:
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Internal Server Error: Tipe data User ID tidak dikenali",
-			"success": false,
-		})
+	userID, err := helpers.GetUserIDFromContext(c)
+	if err != nil {
+		return err
 	}
 
-	studentID, err := s.repo.GetStudentIDByUserID(c.Context(), userID)
+	studentID, err := s.repo.GetStudentIDByUserID(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{
-			"message": "Data mahasiswa tidak ditemukan untuk user ini",
-			"success": false,
-		})
+		return err
+	}
+	if err := s.ensureStudentActive(c, studentID); err != nil {
+		return err
 	}
 
 	mongoData := models.AchievementMongo{
@@ -79,13 +64,9 @@ func (s *achievementService) CreateAchievement(c *fiber.Ctx) error {
 		UpdatedAt:       time.Now(),
 	}
 
-	mongoID, err := s.repo.CreateAchievementMongo(c.Context(), mongoData)
+	mongoID, err := s.repo.CreateAchievementMongo(c.UserContext(), mongoData)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal menyimpan data detail prestasi",
-			"success": false,
-			"error":   err.Error(),
-		})
+		return err
 	}
 
 	pgRef := models.AchievementReference{
@@ -95,17 +76,14 @@ func (s *achievementService) CreateAchievement(c *fiber.Ctx) error {
 		Status:             "draft",
 	}
 
-	err = s.repo.CreateAchievementReference(c.Context(), pgRef)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal menyimpan referensi prestasi",
-			"success": false,
-			"error":   err.Error(),
-		})
+	if err := s.repo.CreateAchievementReference(c.UserContext(), pgRef); err != nil {
+		return err
 	}
 
+	metrics.AchievementEvent(metrics.AchievementCreated)
+
 	return c.Status(201).JSON(fiber.Map{
-		"message": "Prestasi berhasil dibuat (Draft)",
+		"message": helpers.Message(c, i18n.MsgAchievementCreated),
 		"success": true,
 		"data": fiber.Map{
 			"id":                   pgRef.ID,
@@ -120,40 +98,18 @@ func (s *achievementService) UpdateAchievement(c *fiber.Ctx) error {
     id := c.Params("id")
 
     var req models.CreateAchievementRequest
-    if err := c.BodyParser(&req); err != nil {
-        return c.Status(400).JSON(fiber.Map{"message": "Format data salah", "success": false})
-    }
-
-    userIDLocal := c.Locals("user_id")
-    if userIDLocal == nil {
-        return c.Status(401).JSON(fiber.Map{"message": "Unauthorized"})
-    }
-
-    var userID string
-    switch v := userIDLocal.(type) {
-    case string: userID = v
-    case uuid.UUID: userID = v.String()
+    if err := helpers.ParseAndValidate(c, &req); err != nil {
+        return err
     }
 
-    studentID, err := s.repo.GetStudentIDByUserID(c.Context(), userID)
+    // Kepemilikan sudah divalidasi oleh policy di middleware RequirePermission
+    existingData, err := s.repo.GetAchievementByID(c.UserContext(), id)
     if err != nil {
-        return c.Status(403).JSON(fiber.Map{"message": "User bukan mahasiswa"})
-    }
-
-    existingData, err := s.repo.GetAchievementByID(c.Context(), id)
-    if err != nil {
-        return c.Status(404).JSON(fiber.Map{"message": "Prestasi tidak ditemukan"})
-    }
-
-    if existingData.StudentID != studentID {
-        return c.Status(403).JSON(fiber.Map{"message": "Anda tidak berhak mengedit data ini"})
+        return err
     }
 
     if existingData.Status != "draft" {
-        return c.Status(400).JSON(fiber.Map{
-            "message": "Gagal update: Hanya status 'draft' yang boleh diedit",
-            "current_status": existingData.Status,
-        })
+        return errAchievementNotDraft(existingData.Status)
     }
 
     mongoData := models.AchievementMongo{
@@ -164,95 +120,62 @@ func (s *achievementService) UpdateAchievement(c *fiber.Ctx) error {
         Tags:            req.Tags,
     }
 
-    err = s.repo.UpdateAchievement(c.Context(), existingData.ID, existingData.MongoAchievementID, mongoData)
+    err = s.repo.UpdateAchievement(c.UserContext(), existingData.ID, existingData.MongoAchievementID, mongoData)
     if err != nil {
-        return c.Status(500).JSON(fiber.Map{"message": "Gagal mengupdate data"})
+        return err
     }
 
-    return c.JSON(fiber.Map{"message": "Prestasi berhasil diupdate", "success": true})
+    return c.JSON(fiber.Map{"message": helpers.Message(c, i18n.MsgAchievementUpdated), "success": true})
 }
 
 func (s *achievementService) DeleteAchievement(c *fiber.Ctx) error {
     id := c.Params("id")
 
-    userIDLocal := c.Locals("user_id")
-    var userID string
-    switch v := userIDLocal.(type) {
-    case string: userID = v
-    case uuid.UUID: userID = v.String()
-    }
-
-    studentID, err := s.repo.GetStudentIDByUserID(c.Context(), userID)
-    if err != nil {
-        return c.Status(403).JSON(fiber.Map{"message": "User bukan mahasiswa"})
-    }
-
-    existingData, err := s.repo.GetAchievementByID(c.Context(), id)
+    // Kepemilikan sudah divalidasi oleh policy di middleware RequirePermission
+    existingData, err := s.repo.GetAchievementByID(c.UserContext(), id)
     if err != nil {
-        return c.Status(404).JSON(fiber.Map{"message": "Prestasi tidak ditemukan"})
-    }
-
-    if existingData.StudentID != studentID {
-        return c.Status(403).JSON(fiber.Map{"message": "Anda tidak berhak menghapus data ini"})
+        return err
     }
 
     if existingData.Status != "draft" {
-        return c.Status(400).JSON(fiber.Map{
-            "message": "Gagal hapus: Hanya status 'draft' yang boleh dihapus",
-        })
+        return errAchievementNotDraft(existingData.Status)
     }
 
-    err = s.repo.SoftDeleteAchievement(c.Context(), existingData.ID, existingData.MongoAchievementID)
+    err = s.repo.SoftDeleteAchievement(c.UserContext(), existingData.ID, existingData.MongoAchievementID)
     if err != nil {
-        return c.Status(500).JSON(fiber.Map{"message": "Gagal menghapus data"})
+        return err
     }
 
-    return c.JSON(fiber.Map{"message": "Prestasi berhasil dihapus", "success": true})
+    return c.JSON(fiber.Map{"message": helpers.Message(c, i18n.MsgAchievementDeleted), "success": true})
 }
 
 func (s *achievementService) SubmitAchievement(c *fiber.Ctx) error {
     id := c.Params("id")
 
-    // 1. Ambil User ID & Student ID (Standard Auth Check)
-    userIDLocal := c.Locals("user_id")
-    var userID string
-    switch v := userIDLocal.(type) {
-    case string: userID = v
-    case uuid.UUID: userID = v.String()
-    }
-
-    studentID, err := s.repo.GetStudentIDByUserID(c.Context(), userID)
-    if err != nil {
-        return c.Status(403).JSON(fiber.Map{"message": "User bukan mahasiswa"})
-    }
-
-    // 2. Cek Data Existing
-    achievement, err := s.repo.GetAchievementByID(c.Context(), id)
+    // 1. Cek Data Existing (kepemilikan sudah divalidasi oleh policy di middleware RequirePermission)
+    achievement, err := s.repo.GetAchievementByID(c.UserContext(), id)
     if err != nil {
-        return c.Status(404).JSON(fiber.Map{"message": "Prestasi tidak ditemukan"})
-    }
-
-    // 3. Validasi Kepemilikan
-    if achievement.StudentID != studentID {
-        return c.Status(403).JSON(fiber.Map{"message": "Anda tidak berhak mensubmit data ini"})
+        return err
     }
 
     if achievement.Status != "draft" {
-        return c.Status(400).JSON(fiber.Map{
-            "message": "Gagal submit: Hanya prestasi berstatus 'draft' yang bisa disubmit",
-            "current_status": achievement.Status,
-        })
+        return errAchievementNotDraft(achievement.Status)
+    }
+    if err := s.ensureStudentActive(c, achievement.StudentID); err != nil {
+        return err
     }
 
-    // 5. Lakukan Submit
-    err = s.repo.SubmitAchievement(c.Context(), id)
+    // 2. Lakukan Submit
+    err = s.repo.SubmitAchievement(c.UserContext(), id)
     if err != nil {
-        return c.Status(500).JSON(fiber.Map{"message": "Gagal melakukan submit prestasi"})
+        return err
     }
 
+    metrics.AchievementEvent(metrics.AchievementSubmitted)
+
     return c.JSON(fiber.Map{
         "success": true,
-        "message": "Prestasi berhasil disubmit dan menunggu verifikasi",
+        "message": helpers.Message(c, i18n.MsgAchievementSubmitted),
         "data": fiber.Map{
             "id": id,
             "status": "submitted",
@@ -266,42 +189,67 @@ func (s *achievementService) VerifyAchievement(c *fiber.Ctx) error {
 
 	verifierUserID, err := helpers.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"message": err.Error()})
+		return err
 	}
 
-	if err := helpers.ValidateAdvisorAccess(c.Context(), s.repo, achievementID, verifierUserID); err != nil {
-		return c.Status(403).JSON(fiber.Map{"message": err.Error()})
+	if err := helpers.ValidateSubmittedAchievement(c.UserContext(), s.repo, achievementID); err != nil {
+		return err
 	}
 
-	err = s.repo.VerifyAchievement(c.Context(), achievementID, verifierUserID)
+	err = s.repo.VerifyAchievement(c.UserContext(), achievementID, verifierUserID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"message": "Gagal memverifikasi prestasi"})
+		return err
 	}
 
-	return c.JSON(fiber.Map{"success": true, "message": "Prestasi berhasil diverifikasi"})
+	metrics.AchievementEvent(metrics.AchievementVerified)
+	helpers.RecordAudit(c, s.auditRepo, models.AuditAchievementVerify, "achievement", achievementID,
+		fiber.Map{"status": "submitted"}, fiber.Map{"status": "verified", "verified_by": verifierUserID})
+
+	return c.JSON(fiber.Map{"success": true, "message": helpers.Message(c, i18n.MsgAchievementVerified)})
 }
 
 func (s *achievementService) RejectAchievement(c *fiber.Ctx) error {
 	achievementID := c.Params("id")
 
 	var req models.RejectAchievementRequest
-	if err := c.BodyParser(&req); err != nil || req.RejectionNote == "" {
-		return c.Status(400).JSON(fiber.Map{"message": "Alasan penolakan (rejection_note) wajib diisi"})
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
 	}
 
 	verifierUserID, err := helpers.GetUserIDFromContext(c)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"message": err.Error()})
+		return err
 	}
 
-	if err := helpers.ValidateAdvisorAccess(c.Context(), s.repo, achievementID, verifierUserID); err != nil {
-		return c.Status(403).JSON(fiber.Map{"message": err.Error()})
+	if err := helpers.ValidateSubmittedAchievement(c.UserContext(), s.repo, achievementID); err != nil {
+		return err
+	}
+
+	err = s.repo.RejectAchievement(c.UserContext(), achievementID, verifierUserID, req.RejectionNote)
+	if err != nil {
+		return err
 	}
 
-	err = s.repo.RejectAchievement(c.Context(), achievementID, verifierUserID, req.RejectionNote)
+	metrics.AchievementEvent(metrics.AchievementRejected)
+	helpers.RecordAudit(c, s.auditRepo, models.AuditAchievementReject, "achievement", achievementID,
+		fiber.Map{"status": "submitted"}, fiber.Map{"status": "rejected", "verified_by": verifierUserID, "rejection_note": req.RejectionNote})
+
+	return c.JSON(fiber.Map{"success": true, "message": helpers.Message(c, i18n.MsgAchievementRejected)})
+}
+
+// ensureStudentActive menolak pembuatan/pengajuan prestasi oleh mahasiswa yang cuti, lulus atau keluar
+func (s *achievementService) ensureStudentActive(c *fiber.Ctx, studentID string) error {
+	status, err := s.repo.GetStudentStatus(c.UserContext(), studentID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"message": "Gagal menolak prestasi"})
+		return err
 	}
+	if status != models.StudentActive {
+		return apperror.Conflict(apperror.CodeStudentNotActive).With("status", status)
+	}
+	return nil
+}
 
-	return c.JSON(fiber.Map{"success": true, "message": "Prestasi berhasil ditolak"})
+// errAchievementNotDraft menolak perubahan prestasi yang sudah keluar dari status draft
+func errAchievementNotDraft(currentStatus string) error {
+	return apperror.Conflict(apperror.CodeAchievementNotDraft).With("current_status", currentStatus)
 }
\ No newline at end of file
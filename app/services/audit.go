@@ -0,0 +1,94 @@
+package services
+
+import (
+	"time"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/helpers"
+	"uas/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditService interface {
+	GetAuditLogs(c *fiber.Ctx) error
+	VerifyAuditChain(c *fiber.Ctx) error
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) GetAuditLogs(c *fiber.Ctx) error {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+
+	var err error
+	if filter.Page, filter.Limit, err = helpers.ParsePageParams(c); err != nil {
+		return err
+	}
+	// Kolom actor_id dan impersonator_id bertipe UUID, nilai lain ditolak sebelum sampai ke query
+	if filter.ActorID, err = helpers.ParseUUIDQuery(c, "actor_id"); err != nil {
+		return err
+	}
+	if filter.ImpersonatorID, err = helpers.ParseUUIDQuery(c, "impersonator_id"); err != nil {
+		return err
+	}
+
+	// Filter rentang waktu (format RFC3339, contoh: 2025-01-01T00:00:00Z)
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if raw := c.Query(param); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return apperror.BadRequest(apperror.CodeInvalidQuery).With("param", param)
+			}
+			*target = &t
+		}
+	}
+
+	logs, total, err := s.repo.List(c.UserContext(), filter)
+	if err != nil {
+		return err
+	}
+
+	if logs == nil {
+		logs = []models.AuditLog{}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgAuditFetched),
+		"success": true,
+		"data":    logs,
+		"meta": fiber.Map{
+			"page":  filter.Page,
+			"limit": filter.Limit,
+			"total": total,
+		},
+	})
+}
+
+func (s *auditService) VerifyAuditChain(c *fiber.Ctx) error {
+	status, err := s.repo.VerifyChain(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	message := helpers.Message(c, i18n.MsgAuditChainValid)
+	if !status.Valid {
+		message = helpers.Message(c, i18n.MsgAuditChainBroken)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"success": true,
+		"data":    status,
+	})
+}
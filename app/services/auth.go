@@ -1,50 +1,118 @@
 package services
 
 import (
-	"database/sql"
-	"uas/app/models"
-	"uas/app/repository"
-	"uas/database"
-	"uas/utils"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
+    "context"
+    "fmt"
+    "log/slog"
+    "time"
+    "uas/app/apperror"
+    "uas/app/models"
+    "uas/app/repository"
+    "uas/config"
+    "uas/helpers"
+    "uas/i18n"
+    "uas/metrics"
+    "uas/utils"
+
+    "github.com/gofiber/fiber/v2"
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/google/uuid"
 )
 
-func Login(c *fiber.Ctx) error {
-    var req models.LoginRequest
+type AuthService interface {
+    Login(c *fiber.Ctx) error
+    Refresh(c *fiber.Ctx) error
+    GetProfile(c *fiber.Ctx) error
+    UpdatePreferences(c *fiber.Ctx) error
+    Logout(c *fiber.Ctx) error
+    GetSessions(c *fiber.Ctx) error
+    RevokeSession(c *fiber.Ctx) error
+    ForceLogout(c *fiber.Ctx) error
+}
+
+type authService struct {
+    userRepo    repository.UserRepository
+    sessionRepo repository.SessionRepository
+    auditRepo   repository.AuditRepository
+    jwtConfig   config.JWTConfig
+}
+
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, auditRepo repository.AuditRepository, jwtConfig config.JWTConfig) AuthService {
+    return &authService{userRepo: userRepo, sessionRepo: sessionRepo, auditRepo: auditRepo, jwtConfig: jwtConfig}
+}
 
-    if err := c.BodyParser(&req); err != nil {
-        return c.Status(400).JSON(fiber.Map{"error": "Request body tidak valid"})
+// LoadRevokedSessions mengisi ulang denylist dengan sesi yang dicabut selama umur access token terpanjang
+// (termasuk token impersonasi), agar token dari sesi tersebut tetap ditolak setelah server restart
+func LoadRevokedSessions(sessionRepo repository.SessionRepository) {
+    ids, err := sessionRepo.GetRevokedSessionIDsSince(context.Background(), time.Now().Add(-utils.MaxAccessTokenLifetime()))
+    if err != nil {
+        slog.Error("gagal memuat denylist sesi", "error", err)
+        return
+    }
+
+    for _, id := range ids {
+        utils.DenySession(id)
     }
+}
+
+func (s *authService) Login(c *fiber.Ctx) error {
+    var req models.LoginRequest
 
-    if req.Username == "" || req.Password == "" {
-        return c.Status(400).JSON(fiber.Map{"error": "Username dan password harus diisi"})
+    if err := helpers.ParseAndValidate(c, &req); err != nil {
+        return err
     }
 
-    user, err := repository.Login(req.Username)
+    // Username tidak dikenal dan password salah sengaja dibuat sama agar username tidak bisa ditebak
+    invalidCredentials := apperror.Unauthorized(apperror.CodeInvalidCredentials)
+
+    user, err := s.userRepo.Login(c.UserContext(), req.Username)
     if err != nil {
-        if err == sql.ErrNoRows {
-            return c.Status(401).JSON(fiber.Map{"error": "Username salah"})
+        if apperror.IsNotFound(err) {
+            metrics.LoginFailed("unknown_user")
+            return invalidCredentials
         }
-        return c.Status(500).JSON(fiber.Map{"error": "Terjadi kesalahan pada server"})
+        return err
     }
 
     if !utils.CheckPassword(req.Password, user.PasswordHash) {
-        return c.Status(401).JSON(fiber.Map{"error": "password salah"})
+        metrics.LoginFailed("wrong_password")
+        return invalidCredentials
     }
 
     if !user.IsActive {
-        return c.Status(403).JSON(fiber.Map{"error": "Akun anda dinonaktifkan. Silahkan hubungi admin."})
+        metrics.LoginFailed("inactive")
+        return apperror.Forbidden(apperror.CodeAccountInactive)
+    }
+
+    // Catat sesi login untuk perangkat ini
+    device := req.Device
+    if device == "" {
+        device = c.Get(fiber.HeaderUserAgent)
+    }
+    if len(device) > 255 {
+        device = device[:255]
+    }
+
+    session := models.Session{
+        ID:        uuid.New(),
+        UserID:    user.ID,
+        Device:    device,
+        IP:        c.IP(),
+        UserAgent: c.Get(fiber.HeaderUserAgent),
+        CreatedAt: time.Now(),
+        ExpiresAt: time.Now().Add(s.jwtConfig.RefreshTTL),
     }
 
-    accessToken, err := utils.GenerateToken(user)
+    if err := s.sessionRepo.CreateSession(c.UserContext(), session); err != nil {
+        return err
+    }
+
+    accessToken, err := utils.GenerateToken(user, session.ID.String())
     if err != nil {
-        return c.Status(500).JSON(fiber.Map{"error": "Gagal generate token"})
+        return apperror.Internal(fmt.Errorf("gagal generate token: %w", err))
     }
 
-    refreshToken, _ := utils.GenerateRefreshToken(user)
+    refreshToken, _ := utils.GenerateRefreshToken(user, session.ID.String())
 
     userResponse := models.UserResponseDTO{
         ID:       user.ID,
@@ -53,8 +121,11 @@ func Login(c *fiber.Ctx) error {
         Role:     user.RoleName,
     }
 
+    metrics.LoginSucceeded()
+
     return c.Status(200).JSON(fiber.Map{
-        "status": "success",
+        "success": true,
+        "message": helpers.Message(c, i18n.MsgAuthLogin),
         "data": models.LoginResponse{
             Token:        accessToken,
             RefreshToken: refreshToken,
@@ -63,14 +134,13 @@ func Login(c *fiber.Ctx) error {
     })
 }
 
-func Refresh(c *fiber.Ctx) error {
-    var req struct {
-        RefreshToken string `json:"refreshToken"`
+func (s *authService) Refresh(c *fiber.Ctx) error {
+    var req models.RefreshTokenRequest
+    if err := helpers.ParseAndValidate(c, &req); err != nil {
+        return err
     }
 
-    if err := c.BodyParser(&req); err != nil {
-        return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
-    }
+    invalidRefreshToken := apperror.Unauthorized(apperror.CodeRefreshTokenInvalid)
 
     // Parse token
     token, err := jwt.Parse(req.RefreshToken, func(t *jwt.Token) (interface{}, error) {
@@ -78,58 +148,217 @@ func Refresh(c *fiber.Ctx) error {
     })
 
     if err != nil || !token.Valid {
-        return c.Status(401).JSON(fiber.Map{"error": "Invalid refresh token"})
+        return invalidRefreshToken
     }
 
     claims := token.Claims.(jwt.MapClaims)
 
     // Pastikan refresh token
     if claims["type"] != "refresh" {
-        return c.Status(401).JSON(fiber.Map{"error": "Invalid token type"})
+        return invalidRefreshToken
     }
 
-    // Ambil userId dari JWT claim
-    userIDStr := claims["userId"].(string)
-
-    // Convert ke UUID
+    // Ambil userId dari JWT claim lalu convert ke UUID
+    userIDStr, _ := claims["userId"].(string)
     userUUID, err := uuid.Parse(userIDStr)
     if err != nil {
-        return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+        return invalidRefreshToken
     }
 
-    // Ambil DB instance (tanpa modif repository)
-    db := database.ConnectDB()
+    // Pastikan sesi masih aktif (belum logout / dicabut admin)
+    sessionID, _ := claims["sid"].(string)
+    session, err := s.sessionRepo.GetActiveSession(c.UserContext(), sessionID)
+    if err != nil && !apperror.IsNotFound(err) {
+        return err
+    }
+    if err != nil || session.UserID != userUUID {
+        return apperror.Unauthorized(apperror.CodeSessionRevoked)
+    }
 
     // Ambil user lengkap dari database
-    user, err := repository.GetUserByID(db, userUUID)
-    if err != nil {
-        return c.Status(401).JSON(fiber.Map{"error": "User not found"})
+    user, err := s.userRepo.GetUserByID(c.UserContext(), userUUID)
+    if apperror.IsNotFound(err) {
+        return invalidRefreshToken
+    } else if err != nil {
+        return err
+    }
+
+    // User yang dinonaktifkan atau dihapus tidak boleh memperpanjang sesi lamanya
+    if !user.IsActive || user.DeletedAt != nil {
+        return apperror.Forbidden(apperror.CodeAccountInactive)
+    }
+
+    if err := s.sessionRepo.TouchSession(c.UserContext(), sessionID); err != nil {
+        utils.Logger(c.UserContext()).Warn("gagal update last seen sesi", "session_id", sessionID, "error", err)
     }
 
     // Generate access token baru
-    newAccessToken, err := utils.GenerateToken(user)
+    newAccessToken, err := utils.GenerateToken(user, sessionID)
     if err != nil {
-        return c.Status(500).JSON(fiber.Map{"error": "Failed to generate access token"})
+        return apperror.Internal(fmt.Errorf("gagal generate access token: %w", err))
     }
 
     return c.JSON(fiber.Map{
-        "status": "success",
-        "token":  newAccessToken,
+        "success": true,
+        "message": helpers.Message(c, i18n.MsgAuthTokenRefreshed),
+        "data": fiber.Map{
+            "token": newAccessToken,
+        },
     })
 }
 
-func GetProfile(c *fiber.Ctx) error { 
+func (s *authService) GetProfile(c *fiber.Ctx) error { 
     userID := c.Locals("user_id").(uuid.UUID) 
     username := c.Locals("username").(string) 
     role := c.Locals("role_name").(string) 
+
+    data := fiber.Map{ 
+        "user_id":  userID, 
+        "username": username, 
+        "role":     role, 
+    }
+
+    // Tampilkan admin asli jika sesi ini adalah impersonasi
+    if actor, ok := c.Locals("actor").(*models.ActorClaim); ok && actor != nil {
+        data["act"] = actor
+    }
  
     return c.JSON(fiber.Map{ 
         "success": true, 
-        "message": "Profile berhasil diambil", 
-        "data": fiber.Map{ 
-            "user_id":  userID, 
-            "username": username, 
-            "role":     role, 
-        }, 
+        "message": helpers.Message(c, i18n.MsgAuthProfileFetched), 
+        "data": data, 
     }) 
-}
\ No newline at end of file
+}
+
+// UpdatePreferences menyimpan bahasa pilihan user dan menerbitkan access token baru untuk sesi yang sama,
+// sehingga preferensi langsung berlaku tanpa perlu login ulang
+func (s *authService) UpdatePreferences(c *fiber.Ctx) error {
+    var req models.UpdatePreferencesRequest
+    if err := helpers.ParseAndValidate(c, &req); err != nil {
+        return err
+    }
+
+    userID := c.Locals("user_id").(uuid.UUID)
+    sessionID, _ := c.Locals("session_id").(string)
+
+    if err := s.userRepo.UpdatePreferredLanguage(c.UserContext(), userID, req.Language); err != nil {
+        return err
+    }
+
+    user, err := s.userRepo.GetUserByID(c.UserContext(), userID)
+    if err != nil {
+        return err
+    }
+
+    accessToken, err := utils.GenerateToken(user, sessionID)
+    if err != nil {
+        return apperror.Internal(fmt.Errorf("gagal generate token: %w", err))
+    }
+
+    lang, _ := i18n.Parse(req.Language)
+    helpers.SetLang(c, lang)
+
+    return c.JSON(fiber.Map{
+        "success": true,
+        "message": helpers.Message(c, i18n.MsgAuthPreferencesUpdated),
+        "data": fiber.Map{
+            "language": user.PreferredLanguage,
+            "token":    accessToken,
+        },
+    })
+}
+
+func (s *authService) Logout(c *fiber.Ctx) error {
+    userID := c.Locals("user_id").(uuid.UUID)
+    sessionID, _ := c.Locals("session_id").(string)
+    if sessionID == "" {
+        return apperror.BadRequest(apperror.CodeSessionNotBound)
+    }
+
+    err := s.sessionRepo.RevokeSession(c.UserContext(), sessionID, userID.String(), userID.String())
+    if err != nil && !apperror.IsNotFound(err) {
+        return err
+    }
+    utils.DenySession(sessionID)
+
+    return c.JSON(fiber.Map{
+        "success": true,
+        "message": helpers.Message(c, i18n.MsgAuthLogout),
+    })
+}
+
+func (s *authService) GetSessions(c *fiber.Ctx) error {
+    userID := c.Locals("user_id").(uuid.UUID)
+    currentSessionID, _ := c.Locals("session_id").(string)
+
+    sessions, err := s.sessionRepo.GetActiveSessionsByUserID(c.UserContext(), userID.String())
+    if err != nil {
+        return err
+    }
+
+    if sessions == nil {
+        sessions = []models.Session{}
+    }
+    for i := range sessions {
+        sessions[i].Current = sessions[i].ID.String() == currentSessionID
+    }
+
+    return c.JSON(fiber.Map{
+        "success": true,
+        "message": helpers.Message(c, i18n.MsgAuthSessionsFetched),
+        "data":    sessions,
+    })
+}
+
+func (s *authService) RevokeSession(c *fiber.Ctx) error {
+    userID := c.Locals("user_id").(uuid.UUID)
+    id, err := helpers.ParseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+    sessionID := id.String()
+
+    // Hanya bisa mencabut sesi milik sendiri
+    if err := s.sessionRepo.RevokeSession(c.UserContext(), sessionID, userID.String(), userID.String()); err != nil {
+        return err
+    }
+    utils.DenySession(sessionID)
+
+    return c.JSON(fiber.Map{
+        "success": true,
+        "message": helpers.Message(c, i18n.MsgAuthSessionRevoked),
+    })
+}
+
+func (s *authService) ForceLogout(c *fiber.Ctx) error {
+    targetID, err := helpers.ParseUUIDParam(c, "id")
+    if err != nil {
+        return err
+    }
+
+    adminID := c.Locals("user_id").(uuid.UUID)
+
+    if _, err := s.userRepo.GetUserByID(c.UserContext(), targetID); err != nil {
+        return err
+    }
+
+    revoked, err := s.sessionRepo.RevokeSessionsByUserID(c.UserContext(), targetID.String(), adminID.String())
+    if err != nil {
+        return err
+    }
+
+    for _, id := range revoked {
+        utils.DenySession(id)
+    }
+
+    helpers.RecordAudit(c, s.auditRepo, models.AuditUserForceLogout, "user", targetID.String(),
+        nil, fiber.Map{"revoked_sessions": revoked})
+
+    return c.JSON(fiber.Map{
+        "success": true,
+        "message": helpers.Message(c, i18n.MsgAuthForceLogout),
+        "data": fiber.Map{
+            "revoked_sessions": len(revoked),
+        },
+    })
+}
@@ -1,8 +1,13 @@
 package services
 
 import (
-	"database/sql"
+	"context"
+	"time"
+	"uas/app/apperror"
+	"uas/app/models"
 	"uas/app/repository"
+	"uas/helpers"
+	"uas/i18n"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -10,113 +15,256 @@ import (
 
 type LecturerService interface {
 	GetLecturers(c *fiber.Ctx) error
+	ExportLecturers(c *fiber.Ctx) error
 	GetLecturerByID(c *fiber.Ctx) error
+	CreateLecturer(c *fiber.Ctx) error
+	UpdateLecturer(c *fiber.Ctx) error
 	GetLecturerAdvisees(c *fiber.Ctx) error
+	ReassignAdvisees(c *fiber.Ctx) error
 }
 
 type lecturerService struct {
-	repo repository.LecturerRepository
+	repo      repository.LecturerRepository
+	userRepo  repository.UserRepository
+	auditRepo repository.AuditRepository
 }
 
-func NewLecturerService(repo repository.LecturerRepository) LecturerService {
-	return &lecturerService{repo: repo}
+func NewLecturerService(repo repository.LecturerRepository, userRepo repository.UserRepository, auditRepo repository.AuditRepository) LecturerService {
+	return &lecturerService{repo: repo, userRepo: userRepo, auditRepo: auditRepo}
 }
 
+// GetLecturers mendukung ?q (nama/NIP/email), ?department, ?is_active, ?sort, ?order, ?page/?limit atau ?cursor
 func (s *lecturerService) GetLecturers(c *fiber.Ctx) error {
-	const targetRole = "Dosen Wali"
+	filter, err := parseLecturerFilter(c)
+	if err != nil {
+		return err
+	}
 
-	lecturers, err := s.repo.GetAllLecturersByRole(c.Context(), targetRole)
+	lecturers, meta, err := s.repo.ListLecturers(c.UserContext(), filter)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Terjadi kesalahan server",
-			"success": false,
-			"error":   err.Error(),
-		})
+		return err
 	}
 
-	if len(lecturers) == 0 {
-		return c.JSON(fiber.Map{
-			"message": "Data Dosen Wali tidak ditemukan",
-			"success": true,
-			"data":    []string{},
-		})
+	message := helpers.Message(c, i18n.MsgLecturersFetched)
+	if meta.Total == 0 {
+		message = helpers.Message(c, i18n.MsgLecturersEmpty)
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Data Dosen Wali berhasil diambil",
+		"message": message,
 		"success": true,
 		"data":    lecturers,
+		"meta":    meta,
 	})
 }
 
-func (s *lecturerService) GetLecturerByID(c *fiber.Ctx) error {
-	idParam := c.Params("id")
+// Kolom export dosen wali; Mask diisi untuk data pribadi
+var lecturerExportColumns = []helpers.ExportColumn[models.GetLecture]{
+	{Name: "id", Value: func(l models.GetLecture) interface{} { return l.ID }},
+	{Name: "user_id", Value: func(l models.GetLecture) interface{} { return l.UserID }},
+	{Name: "nip", Value: func(l models.GetLecture) interface{} { return l.LecturerID }, Mask: helpers.MaskIdentifier},
+	{Name: "full_name", Value: func(l models.GetLecture) interface{} { return l.FullName }, Mask: helpers.MaskName},
+	{Name: "username", Value: func(l models.GetLecture) interface{} { return l.Username }, Mask: helpers.MaskName},
+	{Name: "email", Value: func(l models.GetLecture) interface{} { return l.Email }, Mask: helpers.MaskEmail},
+	{Name: "department", Value: func(l models.GetLecture) interface{} { return l.Department }},
+	{Name: "is_active", Value: func(l models.GetLecture) interface{} { return l.IsActive }},
+	{Name: "created_at", Value: func(l models.GetLecture) interface{} { return l.CreatedAt }},
+}
 
-	if _, err := uuid.Parse(idParam); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format ID tidak valid",
-			"success": false,
-		})
+// ExportLecturers memakai filter & sorting yang sama dengan GetLecturers (tanpa pagination),
+// ditambah ?format (csv/xlsx/ndjson), ?columns dan ?mask_pii
+func (s *lecturerService) ExportLecturers(c *fiber.Ctx) error {
+	filter, err := parseLecturerFilter(c)
+	if err != nil {
+		return err
 	}
 
-	lecturer, err := s.repo.GetLecturerByID(c.Context(), idParam)
+	opts, err := helpers.ParseExportOptions(c, helpers.ExportColumnNames(lecturerExportColumns))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return c.Status(404).JSON(fiber.Map{
-				"message": "Dosen tidak ditemukan",
-				"success": false,
-			})
-		}
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Terjadi kesalahan server",
-			"success": false,
-			"error":   err.Error(),
-		})
+		return err
+	}
+
+	helpers.RecordExportAudit(c, s.auditRepo, "lecturer", opts)
+	return helpers.StreamExport(c, opts, "lecturers", lecturerExportColumns, func(ctx context.Context, fn func(models.GetLecture) error) error {
+		return s.repo.ExportLecturers(ctx, filter, fn)
+	})
+}
+
+func parseLecturerFilter(c *fiber.Ctx) (models.LecturerFilter, error) {
+	params, err := helpers.ParseListParams(c, models.LecturerSortFields)
+	if err != nil {
+		return models.LecturerFilter{}, err
+	}
+
+	filter := models.LecturerFilter{ListParams: params, Department: c.Query("department")}
+	if filter.IsActive, err = helpers.ParseBoolQuery(c, "is_active"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func (s *lecturerService) GetLecturerByID(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	lecturer, err := s.repo.GetLecturerByID(c.UserContext(), id.String())
+	if err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Data dosen ditemukan",
+		"message": helpers.Message(c, i18n.MsgLecturerFound),
 		"success": true,
 		"data":    lecturer,
 	})
 }
 
+// CreateLecturer membuat profil dosen untuk user ber-role Dosen Wali yang dibuat tanpa profil
+func (s *lecturerService) CreateLecturer(c *fiber.Ctx) error {
+	var req models.CreateLecturerRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserByID(c.UserContext(), uuid.MustParse(req.UserID))
+	if err != nil {
+		return err
+	}
+	if user.RoleName != models.RoleDosen {
+		return apperror.Conflict(apperror.CodeUserNotLecturer).With("role", user.RoleName)
+	}
+
+	lecture := models.Lecture{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		LectureID:  req.LecturerID,
+		Department: req.Department,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.CreateLecturer(c.UserContext(), lecture); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditLecturerCreate, "lecturer", lecture.ID.String(), nil, lecture)
+
+	return c.Status(201).JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgLecturerCreated),
+		"success": true,
+		"data":    lecture,
+	})
+}
+
+// UpdateLecturer mengganti NIP dan departemen dosen
+func (s *lecturerService) UpdateLecturer(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req models.UpdateLecturerRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetLecturerByID(c.UserContext(), id.String())
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateLecturer(c.UserContext(), id.String(), req); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditLecturerUpdate, "lecturer", id.String(),
+		fiber.Map{"lecturer_id": existing.LecturerID, "department": existing.Department},
+		fiber.Map{"lecturer_id": req.LecturerID, "department": req.Department})
+
+	existing.LecturerID = req.LecturerID
+	existing.Department = req.Department
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgLecturerUpdated),
+		"success": true,
+		"data":    existing,
+	})
+}
+
 func (s *lecturerService) GetLecturerAdvisees(c *fiber.Ctx) error {
-	// 1. Ambil ID Dosen dari parameter URL
-	lecturerID := c.Params("id")
-
-	// 2. Validasi UUID
-	if _, err := uuid.Parse(lecturerID); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format ID Dosen tidak valid",
-			"success": false,
-		})
+	// 1. Ambil & validasi ID Dosen dari parameter URL
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
 	}
 
-	// 3. Panggil Repository (Opsional: Cek dulu apakah Dosennya ada, tapi langsung query juga oke)
-	students, err := s.repo.GetAdviseesByLecturerID(c.Context(), lecturerID)
-	
+	// 2. Panggil Repository (Opsional: Cek dulu apakah Dosennya ada, tapi langsung query juga oke)
+	students, err := s.repo.GetAdviseesByLecturerID(c.UserContext(), id.String())
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Terjadi kesalahan server saat mengambil data bimbingan",
-			"success": false,
-			"error":   err.Error(),
-		})
+		return err
 	}
 
-	// 4. Handle jika data kosong (belum punya anak bimbingan)
+	// 3. Handle jika data kosong (belum punya anak bimbingan)
 	if len(students) == 0 {
 		return c.JSON(fiber.Map{
-			"message": "Dosen ini belum memiliki mahasiswa bimbingan",
+			"message": helpers.Message(c, i18n.MsgLecturerAdviseesEmpty),
 			"success": true,
 			"data":    []string{},
 		})
 	}
 
-	// 5. Return Data
+	// 4. Return Data
 	return c.JSON(fiber.Map{
-		"message": "Data mahasiswa bimbingan berhasil diambil",
+		"message": helpers.Message(c, i18n.MsgLecturerAdviseesFetched),
 		"success": true,
 		"data":    students,
 	})
-}
\ No newline at end of file
+}
+
+// ReassignAdvisees memindahkan mahasiswa bimbingan (semua atau student_ids tertentu) ke dosen lain,
+// misalnya saat dosen wali tidak lagi bertugas
+func (s *lecturerService) ReassignAdvisees(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req models.ReassignAdviseesRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+	if req.ToLecturerID == id.String() {
+		return apperror.BadRequest(apperror.CodeReassignSameLecturer)
+	}
+
+	if _, err := s.repo.GetLecturerByID(c.UserContext(), id.String()); err != nil {
+		return err
+	}
+	// Dosen tujuan yang tidak ada dilaporkan sebagai ADVISOR_NOT_FOUND agar tidak tertukar dengan dosen asal
+	if _, err := s.repo.GetLecturerByID(c.UserContext(), req.ToLecturerID); err != nil {
+		if appErr, ok := apperror.As(err); ok && appErr.Code == apperror.CodeLecturerNotFound {
+			return apperror.Wrap(err, apperror.NotFound(apperror.CodeAdvisorNotFound))
+		}
+		return err
+	}
+
+	moved, err := s.repo.ReassignAdvisees(c.UserContext(), id.String(), req.ToLecturerID, req.StudentIDs)
+	if err != nil {
+		return err
+	}
+
+	if len(moved) > 0 {
+		helpers.RecordAudit(c, s.auditRepo, models.AuditLecturerReassign, "lecturer", id.String(),
+			fiber.Map{"advisor_id": id.String(), "student_ids": moved},
+			fiber.Map{"advisor_id": req.ToLecturerID, "student_ids": moved})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgAdviseesReassigned),
+		"success": true,
+		"data": fiber.Map{
+			"to_lecturer_id": req.ToLecturerID,
+			"moved":          len(moved),
+			"student_ids":    moved,
+		},
+	})
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/helpers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PersonalDataService menyusun seluruh data pribadi user menjadi ZIP untuk permintaan subjek data (UU PDP)
+type PersonalDataService interface {
+	ExportOwnData(c *fiber.Ctx) error
+	ExportUserData(c *fiber.Ctx) error
+}
+
+type personalDataService struct {
+	repo      repository.PersonalDataRepository
+	auditRepo repository.AuditRepository
+}
+
+func NewPersonalDataService(repo repository.PersonalDataRepository, auditRepo repository.AuditRepository) PersonalDataService {
+	return &personalDataService{repo: repo, auditRepo: auditRepo}
+}
+
+// ExportOwnData mengunduh data pribadi user yang sedang login
+func (s *personalDataService) ExportOwnData(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	return s.export(c, userID)
+}
+
+// ExportUserData dipakai admin untuk memenuhi permintaan data pribadi atas nama user lain
+func (s *personalDataService) ExportUserData(c *fiber.Ctx) error {
+	userID, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	return s.export(c, userID.String())
+}
+
+// export menulis setiap bagian data sebagai <nama>.json ditambah manifest.json berisi daftar file,
+// jumlah record dan checksum SHA-256 agar penerima dapat memastikan isi ZIP utuh
+func (s *personalDataService) export(c *fiber.Ctx, userID string) error {
+	sections, err := s.repo.CollectPersonalData(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	requesterID, _ := helpers.GetUserIDFromContext(c)
+	requesterName, _ := c.Locals("username").(string)
+	manifest := models.PersonalDataManifest{
+		FormatVersion: models.PersonalDataFormatVersion,
+		UserID:        userID,
+		GeneratedAt:   time.Now(),
+		RequestedBy:   models.PersonalDataActor{UserID: requesterID, Username: requesterName, Self: requesterID == userID},
+		Files:         make([]models.PersonalDataFile, 0, len(sections)),
+		Excluded:      models.PersonalDataExcluded,
+	}
+	if manifest.RequestedBy.Self {
+		redactThirdPartyAudit(sections, userID)
+		manifest.Excluded = append(append([]string{}, models.PersonalDataExcluded...), models.PersonalDataExcludedSelf...)
+	}
+
+	// ZIP disusun di memori lebih dulu agar kegagalan masih dapat dikirim sebagai respons error biasa
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, section := range sections {
+		name := section.Name + ".json"
+		content, err := json.MarshalIndent(section.Records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("gagal menyusun %s: %w", name, err)
+		}
+		if err := writeZipFile(w, name, content, manifest.GeneratedAt); err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(content)
+		manifest.Files = append(manifest.Files, models.PersonalDataFile{
+			Name:        name,
+			Description: section.Description,
+			Records:     len(section.Records),
+			SHA256:      hex.EncodeToString(sum[:]),
+		})
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("gagal menyusun manifest.json: %w", err)
+	}
+	if err := writeZipFile(w, "manifest.json", content, manifest.GeneratedAt); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("gagal menyusun file ZIP: %w", err)
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditPersonalDataExport, "user", userID, nil,
+		fiber.Map{"self": manifest.RequestedBy.Self, "files": len(manifest.Files)})
+
+	filename := fmt.Sprintf("personal-data-%s-%s.zip", userID, manifest.GeneratedAt.Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Send(buf.Bytes())
+}
+
+// redactThirdPartyAudit menghapus data pihak lain dari audit log pada export mandiri: IP dan user agent
+// entry yang dilakukan (atau diimpersonasi) orang lain, serta snapshot before/after entry yang menyasar
+// data selain akun, profil dan prestasi user sendiri
+func redactThirdPartyAudit(sections []models.PersonalDataSection, userID string) {
+	own := map[string]bool{"user/" + userID: true}
+	var auditLogs []interface{}
+	for _, section := range sections {
+		switch section.Name {
+		case "student", "lecturer":
+			for _, id := range personalRecordIDs(section.Records) {
+				own[section.Name+"/"+id] = true
+			}
+		case "achievement_references":
+			for _, id := range personalRecordIDs(section.Records) {
+				own["achievement/"+id] = true
+			}
+		case "audit_logs":
+			auditLogs = section.Records
+		}
+	}
+
+	for _, record := range auditLogs {
+		entry, ok := record.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		actorID, _ := entry["actor_id"].(string)
+		impersonatorID, _ := entry["impersonator_id"].(string)
+		if actorID != userID || (impersonatorID != "" && impersonatorID != userID) {
+			entry["ip"], entry["user_agent"] = nil, nil
+		}
+
+		targetType, _ := entry["target_type"].(string)
+		targetID, _ := entry["target_id"].(string)
+		if !own[targetType+"/"+targetID] {
+			entry["before"], entry["after"] = nil, nil
+		}
+	}
+}
+
+func personalRecordIDs(records []interface{}) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		if entry, ok := record.(map[string]interface{}); ok {
+			if id, ok := entry["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func writeZipFile(w *zip.Writer, name string, content []byte, modified time.Time) error {
+	f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", name, err)
+	}
+	if _, err := f.Write(content); err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"strings"
+	"time"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/helpers"
+	"uas/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RoleService interface {
+	GetRoles(c *fiber.Ctx) error
+	GetRoleByID(c *fiber.Ctx) error
+	CreateRole(c *fiber.Ctx) error
+	UpdateRole(c *fiber.Ctx) error
+	DeleteRole(c *fiber.Ctx) error
+	GetPermissions(c *fiber.Ctx) error
+	GrantPermission(c *fiber.Ctx) error
+	RevokePermission(c *fiber.Ctx) error
+}
+
+type roleService struct {
+	repo      repository.RoleRepository
+	auditRepo repository.AuditRepository
+}
+
+func NewRoleService(repo repository.RoleRepository, auditRepo repository.AuditRepository) RoleService {
+	return &roleService{repo: repo, auditRepo: auditRepo}
+}
+
+func (s *roleService) GetRoles(c *fiber.Ctx) error {
+	roles, err := s.repo.GetAllRoles(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	if len(roles) == 0 {
+		return c.JSON(fiber.Map{
+			"message": helpers.Message(c, i18n.MsgRolesEmpty),
+			"success": true,
+			"data":    []string{},
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgRolesFetched),
+		"success": true,
+		"data":    roles,
+	})
+}
+
+func (s *roleService) GetRoleByID(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	roleID := id.String()
+
+	role, err := s.repo.GetRoleByID(c.UserContext(), roleID)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := s.repo.GetPermissionsByRoleID(c.UserContext(), roleID)
+	if err != nil {
+		return err
+	}
+
+	if permissions == nil {
+		permissions = []models.Permission{}
+	}
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgRoleFound),
+		"success": true,
+		"data": models.RoleDetail{
+			Role:        role,
+			Permissions: permissions,
+		},
+	})
+}
+
+func (s *roleService) CreateRole(c *fiber.Ctx) error {
+	var req models.CreateRoleRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	role := models.Role{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+	}
+
+	// Nama duplikat dipetakan repository menjadi ROLE_NAME_TAKEN
+	if err := s.repo.CreateRole(c.UserContext(), role); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditRoleCreate, "role", role.ID.String(), nil, role)
+
+	return c.Status(201).JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgRoleCreated),
+		"success": true,
+		"data":    role,
+	})
+}
+
+func (s *roleService) UpdateRole(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	roleID := id.String()
+
+	var req models.CreateRoleRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	existing, err := s.repo.GetRoleByID(c.UserContext(), roleID)
+	if err != nil {
+		return err
+	}
+
+	// Role bawaan dipakai langsung di kode, jadi hanya deskripsinya yang boleh diubah
+	if helpers.IsSystemRole(existing.Name) && existing.Name != req.Name {
+		return apperror.BadRequest(apperror.CodeSystemRoleProtected)
+	}
+
+	if err := s.repo.UpdateRole(c.UserContext(), roleID, req.Name, req.Description); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditRoleUpdate, "role", roleID,
+		fiber.Map{"name": existing.Name, "description": existing.Description},
+		fiber.Map{"name": req.Name, "description": req.Description})
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgRoleUpdated),
+		"success": true,
+	})
+}
+
+func (s *roleService) DeleteRole(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	roleID := id.String()
+
+	existing, err := s.repo.GetRoleByID(c.UserContext(), roleID)
+	if err != nil {
+		return err
+	}
+
+	if helpers.IsSystemRole(existing.Name) {
+		return apperror.BadRequest(apperror.CodeSystemRoleProtected)
+	}
+
+	userCount, err := s.repo.CountUsersByRole(c.UserContext(), roleID)
+	if err != nil {
+		return err
+	}
+
+	if userCount > 0 {
+		return apperror.Conflict(apperror.CodeRoleInUse).With("user_count", userCount)
+	}
+
+	if err := s.repo.DeleteRole(c.UserContext(), roleID); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditRoleDelete, "role", roleID, existing, nil)
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgRoleDeleted),
+		"success": true,
+	})
+}
+
+func (s *roleService) GetPermissions(c *fiber.Ctx) error {
+	permissions, err := s.repo.GetAllPermissions(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	if len(permissions) == 0 {
+		return c.JSON(fiber.Map{
+			"message": helpers.Message(c, i18n.MsgPermissionsEmpty),
+			"success": true,
+			"data":    []string{},
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgPermissionsFetched),
+		"success": true,
+		"data":    permissions,
+	})
+}
+
+func (s *roleService) GrantPermission(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	roleID := id.String()
+
+	var req models.GrantPermissionRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetRoleByID(c.UserContext(), roleID); err != nil {
+		return err
+	}
+
+	permission, err := s.repo.GetPermissionByID(c.UserContext(), req.PermissionID)
+	if err != nil {
+		return err
+	}
+
+	// Grant ulang mengganti scope; menurunkan scope all sama dengan mencabut akses admin efektif
+	if req.Scope != models.ScopeAll {
+		current, err := s.repo.GetPermissionsByRoleID(c.UserContext(), roleID)
+		if err != nil {
+			return err
+		}
+		for _, p := range current {
+			if p.ID != permission.ID || p.Scope != models.ScopeAll {
+				continue
+			}
+			if err := s.ensureAdminPermissionRemains(c, roleID, permission); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.repo.GrantPermission(c.UserContext(), roleID, req.PermissionID, req.Scope); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditRolePermissionGrant, "role", roleID,
+		nil, fiber.Map{"permission_id": req.PermissionID, "scope": req.Scope})
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgRolePermissionGranted),
+		"success": true,
+	})
+}
+
+func (s *roleService) RevokePermission(c *fiber.Ctx) error {
+	roleUUID, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	permissionUUID, err := helpers.ParseUUIDParam(c, "permissionId")
+	if err != nil {
+		return err
+	}
+	roleID, permissionID := roleUUID.String(), permissionUUID.String()
+
+	permission, err := s.repo.GetPermissionByID(c.UserContext(), permissionID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureAdminPermissionRemains(c, roleID, permission); err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokePermission(c.UserContext(), roleID, permissionID); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditRolePermissionRevoke, "role", roleID,
+		fiber.Map{"permission_id": permissionID, "permission": permission.Name}, nil)
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgRolePermissionRevoked),
+		"success": true,
+	})
+}
+
+// ensureAdminPermissionRemains mencegah admin terakhir kehilangan akses manajemen user/role saat role
+// yang sedang dipakai kehilangan permission tersebut atau scope all-nya
+func (s *roleService) ensureAdminPermissionRemains(c *fiber.Ctx, roleID string, permission models.Permission) error {
+	userCount, err := s.repo.CountUsersByRole(c.UserContext(), roleID)
+	if err != nil {
+		return err
+	}
+	if userCount == 0 {
+		return nil
+	}
+	return helpers.ValidateAdminPermissionRemains(c.UserContext(), s.repo, []models.Permission{permission}, roleID, "")
+}
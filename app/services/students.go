@@ -1,85 +1,132 @@
 package services
 
 import (
-	"database/sql"
-	"strings"
+	"context"
 	"uas/app/models"
 	"uas/app/repository"
+	"uas/helpers"
+	"uas/i18n"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 type StudentService interface {
 	GetStudents(c *fiber.Ctx) error
+	ExportStudents(c *fiber.Ctx) error
 	GetStudentByID(c *fiber.Ctx) error
 	UpdateStudentAdvisor(c *fiber.Ctx) error
+	UpdateStudent(c *fiber.Ctx) error
+	UpdateStudentStatus(c *fiber.Ctx) error
+	GetStudentStatusHistory(c *fiber.Ctx) error
 }
 
 type studentService struct {
-	repo repository.StudentRepository
+	repo      repository.StudentRepository
+	auditRepo repository.AuditRepository
 }
 
-func NewStudentService(repo repository.StudentRepository) StudentService {
-	return &studentService{repo: repo}
+func NewStudentService(repo repository.StudentRepository, auditRepo repository.AuditRepository) StudentService {
+	return &studentService{repo: repo, auditRepo: auditRepo}
 }
 
+// GetStudents mendukung ?q (nama/NIM/email), ?program_study, ?academy_year, ?advisor_id, ?status, ?is_active,
+// ?sort, ?order, ?page/?limit atau ?cursor
 func (s *studentService) GetStudents(c *fiber.Ctx) error {
-	const targetRole = "Mahasiswa"
-	
-	students, err := s.repo.GetAllStudentsByRole(c.Context(), targetRole)
-	
+	filter, err := parseStudentFilter(c)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Terjadi kesalahan pada server saat mengambil data mahasiswa",
-			"success": false,
-            "error":   err.Error(),
-		})
+		return err
 	}
 
-	if len(students) == 0 {
-		return c.JSON(fiber.Map{
-			"message": "Data Mahasiswa tidak ditemukan",
-			"success": true,
-			"data":    []string{},
-		})
+	students, meta, err := s.repo.ListStudents(c.UserContext(), filter)
+	if err != nil {
+		return err
+	}
+
+	message := helpers.Message(c, i18n.MsgStudentsFetched)
+	if meta.Total == 0 {
+		message = helpers.Message(c, i18n.MsgStudentsEmpty)
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Data Mahasiswa berhasil diambil",
+		"message": message,
 		"success": true,
 		"data":    students,
+		"meta":    meta,
 	})
 }
 
-func (s *studentService) GetStudentByID(c *fiber.Ctx) error {
-	idParam := c.Params("id")
+// Kolom export mahasiswa, termasuk dosen wali (penugasan bimbingan); Mask diisi untuk data pribadi
+var studentExportColumns = []helpers.ExportColumn[models.GetStudent]{
+	{Name: "id", Value: func(s models.GetStudent) interface{} { return s.ID }},
+	{Name: "user_id", Value: func(s models.GetStudent) interface{} { return s.UserID }},
+	{Name: "nim", Value: func(s models.GetStudent) interface{} { return s.NIM }, Mask: helpers.MaskIdentifier},
+	{Name: "full_name", Value: func(s models.GetStudent) interface{} { return s.FullName }, Mask: helpers.MaskName},
+	{Name: "username", Value: func(s models.GetStudent) interface{} { return s.Username }, Mask: helpers.MaskName},
+	{Name: "email", Value: func(s models.GetStudent) interface{} { return s.Email }, Mask: helpers.MaskEmail},
+	{Name: "program_study", Value: func(s models.GetStudent) interface{} { return s.ProgramStudy }},
+	{Name: "academy_year", Value: func(s models.GetStudent) interface{} { return s.AcademyYear }},
+	{Name: "advisor_id", Value: func(s models.GetStudent) interface{} { return s.AdvisorID }},
+	{Name: "advisor_nip", Value: func(s models.GetStudent) interface{} { return s.AdvisorNIP }, Mask: helpers.MaskIdentifier},
+	{Name: "advisor_name", Value: func(s models.GetStudent) interface{} { return s.AdvisorName }, Mask: helpers.MaskName},
+	{Name: "status", Value: func(s models.GetStudent) interface{} { return s.Status }},
+	{Name: "is_active", Value: func(s models.GetStudent) interface{} { return s.IsActive }},
+}
+
+// ExportStudents memakai filter & sorting yang sama dengan GetStudents (tanpa pagination),
+// ditambah ?format (csv/xlsx/ndjson), ?columns dan ?mask_pii
+func (s *studentService) ExportStudents(c *fiber.Ctx) error {
+	filter, err := parseStudentFilter(c)
+	if err != nil {
+		return err
+	}
+
+	opts, err := helpers.ParseExportOptions(c, helpers.ExportColumnNames(studentExportColumns))
+	if err != nil {
+		return err
+	}
+
+	helpers.RecordExportAudit(c, s.auditRepo, "student", opts)
+	return helpers.StreamExport(c, opts, "students", studentExportColumns, func(ctx context.Context, fn func(models.GetStudent) error) error {
+		return s.repo.ExportStudents(ctx, filter, fn)
+	})
+}
+
+func parseStudentFilter(c *fiber.Ctx) (models.StudentFilter, error) {
+	params, err := helpers.ParseListParams(c, models.StudentSortFields)
+	if err != nil {
+		return models.StudentFilter{}, err
+	}
+
+	filter := models.StudentFilter{
+		ListParams:   params,
+		ProgramStudy: c.Query("program_study"),
+		AcademicYear: c.Query("academy_year"),
+	}
+	if filter.AdvisorID, err = helpers.ParseUUIDQuery(c, "advisor_id"); err != nil {
+		return filter, err
+	}
+	if filter.Status, err = helpers.ParseEnumQuery(c, "status", models.StudentStatuses); err != nil {
+		return filter, err
+	}
+	if filter.IsActive, err = helpers.ParseBoolQuery(c, "is_active"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
 
-	if _, err := uuid.Parse(idParam); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format ID tidak valid",
-			"success": false,
-		})
+func (s *studentService) GetStudentByID(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
 	}
 
-	student, err := s.repo.GetStudentByID(c.Context(), idParam)
-	
+	student, err := s.repo.GetStudentByID(c.UserContext(), id.String())
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return c.Status(404).JSON(fiber.Map{
-				"message": "Data mahasiswa tidak ditemukan",
-				"success": false,
-			})
-		}
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Terjadi kesalahan pada server",
-			"success": false,
-            "error":   err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Data mahasiswa ditemukan",
+		"message": helpers.Message(c, i18n.MsgStudentFound),
 		"success": true,
 		"data":    student,
 	})
@@ -87,61 +134,130 @@ func (s *studentService) GetStudentByID(c *fiber.Ctx) error {
 
 func (s *studentService) UpdateStudentAdvisor(c *fiber.Ctx) error {
 	// 1. Ambil Student ID dari URL
-	studentID := c.Params("id")
-	if _, err := uuid.Parse(studentID); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format Student ID tidak valid",
-			"success": false,
-		})
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
 	}
+	studentID := id.String()
 
-	// 2. Parse Body untuk ambil Advisor ID baru
+	// 2. Parse & validasi body untuk ambil Advisor ID baru
 	var req models.UpdateAdvisorRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format data JSON tidak valid",
-			"success": false,
-		})
-	}
-
-	// 3. Validasi Advisor ID
-	if _, err := uuid.Parse(req.AdvisorID); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format Advisor ID tidak valid",
-			"success": false,
-		})
-	}
-
-	// 4. Panggil Repository
-	err := s.repo.UpdateStudentAdvisor(c.Context(), studentID, req.AdvisorID)
-	if err != nil {
-		// Handle jika student tidak ditemukan
-		if err.Error() == "student_not_found" {
-			return c.Status(404).JSON(fiber.Map{
-				"message": "Data mahasiswa tidak ditemukan",
-				"success": false,
-			})
-		}
-		
-		// Handle jika Advisor ID tidak ada di tabel lectures (Foreign Key Violation)
-		// Error message dari database biasanya mengandung "foreign key constraint"
-		if strings.Contains(err.Error(), "foreign key") {
-			return c.Status(400).JSON(fiber.Map{
-				"message": "ID Dosen Wali tidak ditemukan di database",
-				"success": false,
-			})
-		}
-
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal mengganti dosen wali",
-			"success": false,
-			"error":   err.Error(),
-		})
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	// 3. Ambil data lama untuk audit log
+	existing, err := s.repo.GetStudentByID(c.UserContext(), studentID)
+	if err != nil {
+		return err
+	}
+
+	// 4. Panggil Repository (advisor yang tidak ada dipetakan menjadi ADVISOR_NOT_FOUND)
+	if err := s.repo.UpdateStudentAdvisor(c.UserContext(), studentID, req.AdvisorID); err != nil {
+		return err
 	}
 
+	helpers.RecordAudit(c, s.auditRepo, models.AuditStudentAdvisorUpdate, "student", studentID,
+		fiber.Map{"advisor_id": existing.AdvisorID}, fiber.Map{"advisor_id": req.AdvisorID})
+
 	// 5. Sukses
 	return c.JSON(fiber.Map{
-		"message": "Dosen Wali berhasil diperbarui",
+		"message": helpers.Message(c, i18n.MsgStudentAdvisorUpdated),
 		"success": true,
 	})
-}
\ No newline at end of file
+}
+
+// UpdateStudent mengoreksi NIM, program studi dan angkatan; NIM milik mahasiswa lain menjadi STUDENT_NIM_TAKEN
+func (s *studentService) UpdateStudent(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req models.UpdateStudentRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetStudentByID(c.UserContext(), id.String())
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateStudent(c.UserContext(), id.String(), req); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditStudentUpdate, "student", id.String(),
+		fiber.Map{"student_id": existing.NIM, "program_study": existing.ProgramStudy, "academy_year": existing.AcademyYear},
+		fiber.Map{"student_id": req.StudentID, "program_study": req.ProgramStudy, "academy_year": req.AcademicYear})
+
+	existing.NIM = req.StudentID
+	existing.ProgramStudy = req.ProgramStudy
+	existing.AcademyYear = req.AcademicYear
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgStudentUpdated),
+		"success": true,
+		"data":    existing,
+	})
+}
+
+// UpdateStudentStatus mengubah status studi (active, on_leave, graduated, dropped_out) sesuai aturan transisi
+// models.CanTransitionStudentStatus; setiap perubahan tercatat di riwayat status beserta alasannya
+func (s *studentService) UpdateStudentStatus(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req models.UpdateStudentStatusRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	actorID, err := helpers.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.repo.UpdateStudentStatus(c.UserContext(), id.String(), req.Status, req.Reason, actorID)
+	if err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditStudentStatusUpdate, "student", id.String(),
+		fiber.Map{"status": previous}, fiber.Map{"status": req.Status, "reason": req.Reason})
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgStudentStatusUpdated),
+		"success": true,
+		"data": fiber.Map{
+			"id":          id.String(),
+			"from_status": previous,
+			"status":      req.Status,
+		},
+	})
+}
+
+func (s *studentService) GetStudentStatusHistory(c *fiber.Ctx) error {
+	id, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	// Pastikan mahasiswa ada agar id yang salah tidak dibalas dengan riwayat kosong
+	if _, err := s.repo.GetStudentByID(c.UserContext(), id.String()); err != nil {
+		return err
+	}
+
+	history, err := s.repo.GetStudentStatusHistory(c.UserContext(), id.String())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgStudentStatusHistory),
+		"success": true,
+		"data":    history,
+	})
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/config"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SystemService interface {
+	Healthz(c *fiber.Ctx) error
+	Readyz(c *fiber.Ctx) error
+	GetStatus(c *fiber.Ctx) error
+}
+
+// Batas waktu tiap pengecekan dependensi agar probe tidak menggantung
+const dependencyCheckTimeout = 2 * time.Second
+
+type systemService struct {
+	repo      repository.SystemRepository
+	cfg       *config.Config
+	startedAt time.Time
+}
+
+func NewSystemService(repo repository.SystemRepository, cfg *config.Config) SystemService {
+	return &systemService{repo: repo, cfg: cfg, startedAt: time.Now()}
+}
+
+// Healthz hanya menandakan proses hidup, tanpa menyentuh dependensi
+func (s *systemService) Healthz(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Readyz mengecek PostgreSQL, MongoDB dan versi migration, 503 jika ada yang belum siap.
+// Endpoint ini publik sehingga detail error hanya ditulis ke log; detail lengkap ada di /api/v1/system/status.
+func (s *systemService) Readyz(c *fiber.Ctx) error {
+	report := s.readiness(c.UserContext())
+
+	status := 200
+	if report.Status != models.StatusUp {
+		status = 503
+		logger := utils.Logger(c.UserContext())
+		for name, dep := range report.Checks {
+			if dep.Status != models.StatusUp {
+				logger.Warn("dependensi belum siap", "check", name, "error", dep.Error)
+			}
+		}
+		if report.Migration.Error != "" || report.Migration.Dirty {
+			logger.Warn("migration belum siap", "version", report.Migration.Version, "dirty", report.Migration.Dirty, "error", report.Migration.Error)
+		}
+	}
+	return c.Status(status).JSON(report.Summary())
+}
+
+func (s *systemService) GetStatus(c *fiber.Ctx) error {
+	stats := s.repo.GetPostgresStats()
+
+	status := models.SystemStatus{
+		AppName:   s.cfg.App.Name,
+		Env:       s.cfg.App.Env,
+		Version:   config.Version,
+		StartedAt: s.startedAt,
+		Uptime:    time.Since(s.startedAt).Round(time.Second).String(),
+		Readiness: s.readiness(c.UserContext()),
+		Postgres: models.PostgresPoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDuration:       stats.WaitDuration.String(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), dependencyCheckTimeout)
+	defer cancel()
+	if info, err := s.repo.GetMongoServerInfo(ctx); err == nil {
+		status.Mongo = &info
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    status,
+	})
+}
+
+func (s *systemService) readiness(ctx context.Context) models.ReadinessReport {
+	report := models.ReadinessReport{
+		Status: models.StatusUp,
+		Checks: map[string]models.DependencyCheck{
+			"postgres": check(ctx, s.repo.PingPostgres),
+			"mongo":    check(ctx, s.repo.PingMongo),
+		},
+	}
+
+	for _, dep := range report.Checks {
+		if dep.Status != models.StatusUp {
+			report.Status = models.StatusDown
+		}
+	}
+
+	// Migration yang dirty berarti skema setengah jadi, aplikasi belum siap melayani
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+	migration, err := s.repo.GetMigrationVersion(ctx)
+	switch {
+	case err == sql.ErrNoRows:
+		migration.Error = "belum ada migration yang dijalankan"
+		report.Status = models.StatusDown
+	case err != nil:
+		migration.Error = err.Error()
+		report.Status = models.StatusDown
+	case migration.Dirty:
+		report.Status = models.StatusDown
+	}
+	report.Migration = migration
+
+	return report
+}
+
+func check(ctx context.Context, ping func(ctx context.Context) error) models.DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ping(ctx)
+	result := models.DependencyCheck{
+		Status:    models.StatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = models.StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
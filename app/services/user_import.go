@@ -0,0 +1,501 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/helpers"
+	"uas/i18n"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserImportService interface {
+	ImportUsers(c *fiber.Ctx) error
+}
+
+// Panjang password yang dibuat otomatis jika kolom password kosong
+const generatedPasswordLength = 12
+
+type userImportService struct {
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	lecturerRepo repository.LecturerRepository
+	auditRepo    repository.AuditRepository
+}
+
+func NewUserImportService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, lecturerRepo repository.LecturerRepository, auditRepo repository.AuditRepository) UserImportService {
+	return &userImportService{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		lecturerRepo: lecturerRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+// importRow adalah satu baris file beserta hasil validasinya
+type importRow struct {
+	models.ImportUserRow
+	role      models.Role
+	advisorID uuid.UUID
+	// advisorLine menunjuk baris dosen di file yang sama jika advisor_nip belum terdaftar
+	advisorLine int
+	result      models.ImportRowResult
+}
+
+// ImportUsers menerima multipart "file" (.csv/.xlsx). Secara default hanya validasi (dry run);
+// ?dry_run=false menyimpan baris valid per batch. ?format=csv mengembalikan laporan sebagai file CSV.
+func (s *userImportService) ImportUsers(c *fiber.Ctx) error {
+	dryRun := true
+	if v, err := helpers.ParseBoolQuery(c, "dry_run"); err != nil {
+		return err
+	} else if v != nil {
+		dryRun = *v
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return apperror.BadRequest(apperror.CodeInvalidQuery).With("param", "format")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return apperror.Wrap(err, apperror.BadRequest(apperror.CodeImportFileRequired))
+	}
+
+	header, records, lines, err := helpers.ReadSpreadsheet(file, models.MaxImportRows)
+	if err != nil {
+		return err
+	}
+
+	rows, err := mapImportRows(header, records, lines)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateRows(c, rows); err != nil {
+		return err
+	}
+
+	report := models.ImportReport{DryRun: dryRun, Total: len(rows)}
+	if !dryRun {
+		if err := s.createRows(c, rows); err != nil {
+			return err
+		}
+	}
+
+	lang := helpers.Lang(c)
+	for _, row := range rows {
+		switch row.result.Status {
+		case models.ImportStatusValid:
+			report.Valid++
+		case models.ImportStatusInvalid:
+			report.Invalid++
+		case models.ImportStatusCreated:
+			report.Valid++
+			report.Created++
+		case models.ImportStatusFailed:
+			report.Valid++
+			report.Failed++
+		}
+		for i, f := range row.result.Errors {
+			key := helpers.FieldMessageKey(f.Code)
+			if f.Field == "row" {
+				// Baris yang gagal disimpan memakai kode error domain
+				key = f.Code
+			}
+			row.result.Errors[i].Message = i18n.T(lang, key, f.Params)
+		}
+		report.Rows = append(report.Rows, row.result)
+	}
+
+	if !dryRun && report.Created > 0 {
+		createdIDs := []string{}
+		for _, row := range report.Rows {
+			if row.Status == models.ImportStatusCreated {
+				createdIDs = append(createdIDs, row.UserID)
+			}
+		}
+		helpers.RecordAudit(c, s.auditRepo, models.AuditUserImport, "user", "", nil, fiber.Map{
+			"file":     file.Filename,
+			"total":    report.Total,
+			"created":  report.Created,
+			"failed":   report.Failed,
+			"invalid":  report.Invalid,
+			"user_ids": createdIDs,
+		})
+	}
+
+	if format == "csv" {
+		return writeImportReportCSV(c, report)
+	}
+
+	message := helpers.Message(c, i18n.MsgUsersImportValidated)
+	if !dryRun {
+		message = helpers.Message(c, i18n.MsgUsersImported)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": message,
+		"success": true,
+		"data":    report,
+	})
+}
+
+// mapImportRows memetakan kolom file ke ImportUserRow berdasarkan nama kolom pada header
+func mapImportRows(header []string, records [][]string, lines []int) ([]*importRow, error) {
+	index := map[string]int{}
+	for i, col := range header {
+		index[col] = i
+	}
+
+	missing := []string{}
+	for _, col := range models.ImportRequiredColumns {
+		if _, ok := index[col]; !ok {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, apperror.BadRequest(apperror.CodeImportFileInvalid).With("reason", "missing columns: "+strings.Join(missing, ", "))
+	}
+
+	cell := func(record []string, col string) string {
+		i, ok := index[col]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := make([]*importRow, len(records))
+	for i, record := range records {
+		row := &importRow{ImportUserRow: models.ImportUserRow{
+			Line:         lines[i],
+			Username:     cell(record, "username"),
+			Email:        cell(record, "email"),
+			Password:     cell(record, "password"),
+			FullName:     cell(record, "full_name"),
+			Role:         cell(record, "role"),
+			StudentID:    cell(record, "student_id"),
+			ProgramStudy: cell(record, "program_study"),
+			AcademicYear: cell(record, "academy_year"),
+			AdvisorNIP:   cell(record, "advisor_nip"),
+			LecturerID:   cell(record, "lecturer_id"),
+			Department:   cell(record, "department"),
+		}}
+		row.result = models.ImportRowResult{Line: row.Line, Username: row.Username, Status: models.ImportStatusValid}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// validateRows memeriksa format, role, kolom wajib per role, duplikat di dalam file,
+// nilai yang sudah terdaftar di database dan NIP dosen wali
+func (s *userImportService) validateRows(c *fiber.Ctx, rows []*importRow) error {
+	ctx := c.UserContext()
+
+	roles, err := s.roleRepo.GetAllRoles(ctx)
+	if err != nil {
+		return err
+	}
+	rolesByName := map[string]models.Role{}
+	for _, role := range roles {
+		rolesByName[strings.ToLower(role.Name)] = role
+	}
+
+	for _, row := range rows {
+		if err := helpers.Validate(&row.ImportUserRow); err != nil {
+			appErr, ok := apperror.As(err)
+			if !ok {
+				return err
+			}
+			row.addErrors(appErr.Fields...)
+		}
+
+		role, ok := rolesByName[strings.ToLower(row.Role)]
+		if row.Role != "" && !ok {
+			row.addError("role", "unknown_role", nil)
+		}
+		row.role = role
+
+		switch role.Name {
+		case models.RoleMahasiswa:
+			row.requireFields("student_id", "program_study")
+		case models.RoleDosen:
+			row.requireFields("lecturer_id", "department")
+		}
+	}
+
+	// Nilai unik: dibandingkan dengan baris sebelumnya di file dan dengan database
+	uniqueFields := []struct {
+		field string
+		value func(*importRow) string
+	}{
+		{"username", func(r *importRow) string { return r.Username }},
+		{"email", func(r *importRow) string { return strings.ToLower(r.Email) }},
+		{"student_id", func(r *importRow) string {
+			if r.role.Name != models.RoleMahasiswa {
+				return ""
+			}
+			return r.StudentID
+		}},
+		{"lecturer_id", func(r *importRow) string {
+			if r.role.Name != models.RoleDosen {
+				return ""
+			}
+			return r.LecturerID
+		}},
+	}
+
+	for _, uf := range uniqueFields {
+		firstLine := map[string]int{}
+		values := []string{}
+		for _, row := range rows {
+			v := uf.value(row)
+			if v == "" {
+				continue
+			}
+			if line, dup := firstLine[v]; dup {
+				row.addError(uf.field, "duplicate_in_file", map[string]interface{}{"line": line})
+				continue
+			}
+			firstLine[v] = row.Line
+			values = append(values, v)
+		}
+
+		existing, err := s.userRepo.FindExistingValues(ctx, uf.field, values)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if v := uf.value(row); v != "" && existing[v] {
+				row.addError(uf.field, "already_exists", nil)
+			}
+		}
+	}
+
+	// Dosen wali boleh sudah terdaftar atau ikut diimport di file yang sama
+	nips := []string{}
+	fileLecturers := map[string]int{}
+	for _, row := range rows {
+		if row.role.Name == models.RoleMahasiswa && row.AdvisorNIP != "" {
+			nips = append(nips, row.AdvisorNIP)
+		}
+		if row.role.Name == models.RoleDosen && row.result.Status == models.ImportStatusValid {
+			if _, dup := fileLecturers[row.LecturerID]; !dup {
+				fileLecturers[row.LecturerID] = row.Line
+			}
+		}
+	}
+
+	advisors, err := s.lecturerRepo.GetLecturerIDsByNIP(ctx, nips)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.role.Name != models.RoleMahasiswa || row.AdvisorNIP == "" {
+			continue
+		}
+		if id, ok := advisors[row.AdvisorNIP]; ok {
+			row.advisorID = id
+		} else if line, ok := fileLecturers[row.AdvisorNIP]; ok {
+			row.advisorLine = line
+		} else {
+			row.addError("advisor_nip", "advisor_not_found", nil)
+		}
+	}
+
+	return nil
+}
+
+// createRows menyimpan baris valid per batch. Dosen disimpan lebih dulu agar mahasiswa
+// yang dosen walinya ikut diimport dapat langsung dihubungkan.
+func (s *userImportService) createRows(c *fiber.Ctx, rows []*importRow) error {
+	var lecturers, others []*importRow
+	for _, row := range rows {
+		if row.result.Status != models.ImportStatusValid {
+			continue
+		}
+		if row.role.Name == models.RoleDosen {
+			lecturers = append(lecturers, row)
+		} else {
+			others = append(others, row)
+		}
+	}
+
+	now := time.Now()
+	lecturerIDs := map[int]uuid.UUID{}
+	items := make([]models.ImportUser, 0, len(lecturers)+len(others))
+	pending := make([]*importRow, 0, cap(items))
+	passwords := make([]string, 0, cap(items))
+	for _, row := range append(lecturers, others...) {
+		password := row.Password
+		if password == "" {
+			generated, err := utils.GeneratePassword(generatedPasswordLength)
+			if err != nil {
+				return apperror.Internal(fmt.Errorf("gagal membuat password: %w", err))
+			}
+			password = generated
+			row.result.GeneratedPassword = generated
+		}
+
+		item := models.ImportUser{
+			Line: row.Line,
+			User: models.User{
+				ID:        uuid.New(),
+				Username:  row.Username,
+				Email:     row.Email,
+				FullName:  row.FullName,
+				RoleID:    row.role.ID,
+				RoleName:  row.role.Name,
+				IsActive:  true,
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		}
+
+		switch row.role.Name {
+		case models.RoleMahasiswa:
+			advisorID := row.advisorID
+			if row.advisorLine != 0 {
+				advisorID = lecturerIDs[row.advisorLine]
+			}
+			item.Student = &models.Student{
+				ID:           uuid.New(),
+				UserID:       item.User.ID,
+				StudentID:    row.StudentID,
+				ProgramStudy: row.ProgramStudy,
+				AcademicYear: row.AcademicYear,
+				AdvisorID:    advisorID,
+				CreatedAt:    now,
+			}
+		case models.RoleDosen:
+			item.Lecture = &models.Lecture{
+				ID:         uuid.New(),
+				UserID:     item.User.ID,
+				LectureID:  row.LecturerID,
+				Department: row.Department,
+				CreatedAt:  now,
+			}
+			lecturerIDs[row.Line] = item.Lecture.ID
+		}
+
+		items = append(items, item)
+		pending = append(pending, row)
+		passwords = append(passwords, password)
+	}
+
+	for start := 0; start < len(items); start += models.ImportBatchSize {
+		end := min(start+models.ImportBatchSize, len(items))
+		if err := hashImportPasswords(items[start:end], passwords[start:end]); err != nil {
+			return apperror.Internal(fmt.Errorf("gagal mengenkripsi password: %w", err))
+		}
+		rowErrs, err := s.userRepo.ImportUsers(c.UserContext(), items[start:end])
+		if err != nil {
+			// Batch gagal di-commit: seluruh baris batch ini ditandai gagal, batch berikutnya tetap dicoba
+			utils.Logger(c.UserContext()).Error("gagal menyimpan batch import user", "from_line", items[start].Line, "error", err)
+			rowErrs = make([]error, end-start)
+			for i := range rowErrs {
+				rowErrs[i] = err
+			}
+		}
+
+		for i, rowErr := range rowErrs {
+			row := pending[start+i]
+			if rowErr == nil {
+				row.result.Status = models.ImportStatusCreated
+				row.result.UserID = items[start+i].User.ID.String()
+				continue
+			}
+			row.result.Status = models.ImportStatusFailed
+			row.result.GeneratedPassword = ""
+			code := apperror.CodeInternal
+			if appErr, ok := apperror.As(rowErr); ok {
+				code = appErr.Code
+			}
+			row.result.Errors = append(row.result.Errors, apperror.FieldError{Field: "row", Code: code})
+		}
+	}
+	return nil
+}
+
+// hashImportPasswords meng-hash password satu batch secara paralel, dibatasi jumlah CPU
+// agar bcrypt untuk ribuan baris tidak berjalan serial di dalam request
+func hashImportPasswords(items []models.ImportUser, passwords []string) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			hashed, err := bcrypt.GenerateFromPassword([]byte(passwords[i]), bcrypt.DefaultCost)
+			if err != nil {
+				once.Do(func() { firstErr = err })
+				return
+			}
+			items[i].User.PasswordHash = string(hashed)
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (r *importRow) addError(field string, code string, params map[string]interface{}) {
+	r.addErrors(apperror.FieldError{Field: field, Code: code, Params: params})
+}
+
+func (r *importRow) addErrors(fields ...apperror.FieldError) {
+	if len(fields) == 0 {
+		return
+	}
+	r.result.Status = models.ImportStatusInvalid
+	r.result.Errors = append(r.result.Errors, fields...)
+}
+
+// requireFields menandai kolom yang wajib diisi untuk role tertentu
+func (r *importRow) requireFields(fields ...string) {
+	v := reflect.ValueOf(r.ImportUserRow)
+	t := v.Type()
+	for _, field := range fields {
+		for i := 0; i < t.NumField(); i++ {
+			if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == field && v.Field(i).String() == "" {
+				r.addError(field, "required", nil)
+			}
+		}
+	}
+}
+
+// writeImportReportCSV mengirim laporan per baris sebagai lampiran CSV
+func writeImportReportCSV(c *fiber.Ctx, report models.ImportReport) error {
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="user-import-%s.csv"`, time.Now().Format("20060102-150405")))
+
+	w := csv.NewWriter(c.Response().BodyWriter())
+	w.Write([]string{"line", "username", "status", "user_id", "generated_password", "errors"})
+	for _, row := range report.Rows {
+		errs := make([]string, len(row.Errors))
+		for i, f := range row.Errors {
+			errs[i] = f.Field + ": " + f.Message
+		}
+		w.Write([]string{strconv.Itoa(row.Line), row.Username, row.Status, row.UserID, row.GeneratedPassword, strings.Join(errs, "; ")})
+	}
+	w.Flush()
+	return w.Error()
+}
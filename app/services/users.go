@@ -1,10 +1,15 @@
 package services
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
 	"time"
+	"uas/app/apperror"
 	"uas/app/models"
 	"uas/app/repository"
+	"uas/helpers"
+	"uas/i18n"
+	"uas/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -13,70 +18,132 @@ import (
 
 type UserService interface {
 	GetAllUsers(c *fiber.Ctx) error
+	ExportUsers(c *fiber.Ctx) error
 	GetUserByID(c *fiber.Ctx) error
 	CreateUser(c *fiber.Ctx) error
 	UpdateUser(c *fiber.Ctx) error
 	DeleteUser(c *fiber.Ctx) error
+	RestoreUser(c *fiber.Ctx) error
 	UpdateUserRole(c *fiber.Ctx) error
+	ImpersonateUser(c *fiber.Ctx) error
 }
 
+// Durasi token impersonasi jika tidak diisi
+const defaultImpersonationMinutes = 15
+
 type userService struct {
-	db *sql.DB
+	userRepo    repository.UserRepository
+	roleRepo    repository.RoleRepository
+	sessionRepo repository.SessionRepository
+	auditRepo   repository.AuditRepository
 }
 
-func NewUserService(db *sql.DB) UserService {
-	return &userService{db: db}
+func NewUserService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, sessionRepo repository.SessionRepository, auditRepo repository.AuditRepository) UserService {
+	return &userService{
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		sessionRepo: sessionRepo,
+		auditRepo:   auditRepo,
+	}
 }
 
+// GetAllUsers mendukung ?q (nama/username/email), ?role_id, ?role, ?is_active, ?include_deleted, ?sort, ?order,
+// ?page/?limit atau ?cursor
 func (s *userService) GetAllUsers(c *fiber.Ctx) error {
-	users, err := repository.GetAllUsers(s.db)
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		return err
+	}
+
+	users, meta, err := s.userRepo.ListUsers(c.UserContext(), filter)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Terjadi kesalahan pada server",
-			"success": false,
-		})
+		return err
 	}
 
-	if len(users) == 0 {
-		return c.JSON(fiber.Map{
-			"message": "Data User tidak ditemukan",
-			"success": true,
-			"data":    []string{},
-		})
+	message := helpers.Message(c, i18n.MsgUsersFetched)
+	if meta.Total == 0 {
+		message = helpers.Message(c, i18n.MsgUsersEmpty)
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Data berhasil diambil",
+		"message": message,
 		"success": true,
 		"data":    users,
+		"meta":    meta,
 	})
 }
 
+// Kolom export user; Mask diisi untuk data pribadi
+var userExportColumns = []helpers.ExportColumn[models.User]{
+	{Name: "id", Value: func(u models.User) interface{} { return u.ID.String() }},
+	{Name: "username", Value: func(u models.User) interface{} { return u.Username }, Mask: helpers.MaskName},
+	{Name: "email", Value: func(u models.User) interface{} { return u.Email }, Mask: helpers.MaskEmail},
+	{Name: "full_name", Value: func(u models.User) interface{} { return u.FullName }, Mask: helpers.MaskName},
+	{Name: "role_name", Value: func(u models.User) interface{} { return u.RoleName }},
+	{Name: "is_active", Value: func(u models.User) interface{} { return u.IsActive }},
+	{Name: "created_at", Value: func(u models.User) interface{} { return u.CreatedAt }},
+	{Name: "updated_at", Value: func(u models.User) interface{} { return u.UpdatedAt }},
+	{Name: "deleted_at", Value: func(u models.User) interface{} {
+		if u.DeletedAt == nil {
+			return nil
+		}
+		return *u.DeletedAt
+	}},
+}
+
+// ExportUsers memakai filter & sorting yang sama dengan GetAllUsers (tanpa pagination),
+// ditambah ?format (csv/xlsx/ndjson), ?columns dan ?mask_pii
+func (s *userService) ExportUsers(c *fiber.Ctx) error {
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		return err
+	}
+
+	opts, err := helpers.ParseExportOptions(c, helpers.ExportColumnNames(userExportColumns))
+	if err != nil {
+		return err
+	}
+
+	helpers.RecordExportAudit(c, s.auditRepo, "user", opts)
+	return helpers.StreamExport(c, opts, "users", userExportColumns, func(ctx context.Context, fn func(models.User) error) error {
+		return s.userRepo.ExportUsers(ctx, filter, fn)
+	})
+}
+
+func parseUserFilter(c *fiber.Ctx) (models.UserFilter, error) {
+	params, err := helpers.ParseListParams(c, models.UserSortFields)
+	if err != nil {
+		return models.UserFilter{}, err
+	}
+
+	filter := models.UserFilter{ListParams: params, RoleName: c.Query("role")}
+	if filter.RoleID, err = helpers.ParseUUIDQuery(c, "role_id"); err != nil {
+		return filter, err
+	}
+	if filter.IsActive, err = helpers.ParseBoolQuery(c, "is_active"); err != nil {
+		return filter, err
+	}
+	includeDeleted, err := helpers.ParseBoolQuery(c, "include_deleted")
+	if err != nil {
+		return filter, err
+	}
+	filter.IncludeDeleted = includeDeleted != nil && *includeDeleted
+	return filter, nil
+}
+
 func (s *userService) GetUserByID(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	userID, err := uuid.Parse(idParam)
+	userID, err := helpers.ParseUUIDParam(c, "id")
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format ID tidak valid",
-			"success": false,
-		})
+		return err
 	}
 
-	user, err := repository.GetUserByID(s.db, userID)
-	if err == sql.ErrNoRows {
-		return c.Status(404).JSON(fiber.Map{
-			"message": "User tidak ditemukan",
-			"success": false,
-		})
-	} else if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Terjadi kesalahan server",
-			"success": false,
-		})
+	user, err := s.userRepo.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Data user ditemukan",
+		"message": helpers.Message(c, i18n.MsgUserFound),
 		"success": true,
 		"data":    user,
 	})
@@ -84,43 +151,29 @@ func (s *userService) GetUserByID(c *fiber.Ctx) error {
 
 func (s *userService) CreateUser(c *fiber.Ctx) error {
 	var req models.CreateUserRequest
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format data tidak valid",
-			"success": false,
-		})
+	// Profil wajib dikirim sesuai role agar user tidak tersimpan tanpa data mahasiswa/dosen
+	if req.RoleName == models.RoleMahasiswa && req.Student == nil {
+		return apperror.Validation(apperror.FieldError{Field: "student", Code: "required", Message: i18n.T(i18n.Default, "validation.required", nil)})
+	}
+	if req.RoleName == models.RoleDosen && req.Lecture == nil {
+		return apperror.Validation(apperror.FieldError{Field: "lecture", Code: "required", Message: i18n.T(i18n.Default, "validation.required", nil)})
 	}
 
+	// Format sudah dipastikan oleh tag validate
+	roleID := uuid.MustParse(req.RoleID)
+
 	// Hash password
 	hashedPwd, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal mengenkripsi password",
-			"success": false,
-		})
+		return apperror.Internal(fmt.Errorf("gagal mengenkripsi password: %w", err))
 	}
 
-	// Mulai transaksi
-	tx, err := s.db.Begin()
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal memulai transaksi database",
-			"success": false,
-		})
-	}
-	defer tx.Rollback()
-
 	userID := uuid.New()
 
-	roleID, err := uuid.Parse(req.RoleID)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format Role ID salah",
-			"success": false,
-		})
-	}
-
 	newUser := models.User{
 		ID:           userID,
 		Username:     req.Username,
@@ -134,19 +187,10 @@ func (s *userService) CreateUser(c *fiber.Ctx) error {
 		UpdatedAt:    time.Now(),
 	}
 
-	// Insert user
-	err = repository.CreateUser(tx, newUser)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal menyimpan data user",
-			"success": false,
-			"error":   err.Error(),
-		})
-	}
-
-	// Insert student jika role mahasiswa
+	// Profil mahasiswa jika role mahasiswa
+	var newStudent *models.Student
 	if req.RoleName == "Mahasiswa" && req.Student != nil {
-		newStudent := models.Student{
+		newStudent = &models.Student{
 			ID:           uuid.New(),
 			UserID:       userID,
 			StudentID:    req.Student.StudentID,
@@ -155,161 +199,356 @@ func (s *userService) CreateUser(c *fiber.Ctx) error {
 			AdvisorID:    req.Student.AdvisorID,
 			CreatedAt:    time.Now(),
 		}
-
-		if err := repository.CreateStudent(tx, newStudent); err != nil {
-			return c.Status(500).JSON(fiber.Map{
-				"message": "Gagal menyimpan data mahasiswa",
-				"success": false,
-				"error":   err.Error(),
-			})
-		}
 	}
 
-	// Insert lecturer jika role dosen wali
+	// Profil dosen jika role dosen wali
+	var newLecture *models.Lecture
 	if req.RoleName == "Dosen Wali" && req.Lecture != nil {
-		newLecture := models.Lecture{
+		newLecture = &models.Lecture{
 			ID:         uuid.New(),
 			UserID:     userID,
 			LectureID:  req.Lecture.LectureID,
 			Department: req.Lecture.Department,
 			CreatedAt:  time.Now(),
 		}
-
-		if err := repository.CreateLecture(tx, newLecture); err != nil {
-			return c.Status(500).JSON(fiber.Map{
-				"message": "Gagal menyimpan data dosen",
-				"success": false,
-				"error":   err.Error(),
-			})
-		}
 	}
 
-	// Commit transaksi
-	if err := tx.Commit(); err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal commit transaksi",
-			"success": false,
-		})
+	// User dan profil disimpan dalam satu transaksi di repository
+	if err := s.userRepo.CreateUser(c.UserContext(), newUser, newStudent, newLecture); err != nil {
+		return err
 	}
 
+	helpers.RecordAudit(c, s.auditRepo, models.AuditUserCreate, "user", userID.String(), nil, newUser)
+
 	return c.Status(201).JSON(fiber.Map{
-		"message": "User berhasil dibuat",
+		"message": helpers.Message(c, i18n.MsgUserCreated),
 		"success": true,
 		"data":    newUser,
 	})
 }
 
 func (s *userService) UpdateUser(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	userID, err := uuid.Parse(idParam)
+	userID, err := helpers.ParseUUIDParam(c, "id")
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format ID tidak valid",
-			"success": false,
-		})
+		return err
 	}
 
 	var user models.UpdateUser
-	if err := c.BodyParser(&user); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format data JSON tidak valid",
-			"success": false,
-		})
-	}
-
-	err = repository.UpdateUser(s.db, userID, user)
-	if err == sql.ErrNoRows {
-		return c.Status(404).JSON(fiber.Map{
-			"message": "User tidak ditemukan",
-			"success": false,
-		})
-	} else if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal mengupdate data user",
-			"success": false,
-			"error":   err.Error(),
-		})
+	if err := helpers.ParseAndValidate(c, &user); err != nil {
+		return err
+	}
+
+	existing, err := s.userRepo.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+	// is_active tidak boleh dipakai untuk menghidupkan user terhapus, gunakan restore
+	if existing.DeletedAt != nil {
+		return apperror.Conflict(apperror.CodeUserDeleted)
+	}
+	// Role hanya dapat diganti lewat PUT /users/:id/role, tetapi menonaktifkan user juga
+	// tidak boleh menghilangkan pemegang aktif terakhir permission users:*
+	if existing.IsActive && !user.IsActive {
+		if err := s.ensureAdminPermissionRemains(c, existing); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userRepo.UpdateUser(c.UserContext(), userID, user); err != nil {
+		return err
+	}
+
+	// Sama seperti soft delete, user yang dinonaktifkan langsung kehilangan seluruh sesinya
+	if existing.IsActive && !user.IsActive {
+		actorID := c.Locals("user_id").(uuid.UUID)
+		revoked, err := s.sessionRepo.RevokeSessionsByUserID(c.UserContext(), userID.String(), actorID.String())
+		if err != nil {
+			return err
+		}
+		for _, id := range revoked {
+			utils.DenySession(id)
+		}
+	}
+
+	before := models.UpdateUser{
+		Username: existing.Username,
+		Email:    existing.Email,
+		FullName: existing.FullName,
+		IsActive: existing.IsActive,
 	}
+	helpers.RecordAudit(c, s.auditRepo, models.AuditUserUpdate, "user", userID.String(), before, user)
 
 	return c.JSON(fiber.Map{
-		"message": "User berhasil diupdate",
+		"message": helpers.Message(c, i18n.MsgUserUpdated),
 		"success": true,
 		"data":    user,
 	})
 }
 
+// ensureAdminPermissionRemains menolak perubahan jika user adalah pemegang aktif terakhir salah satu permission users:* atau roles:*
+func (s *userService) ensureAdminPermissionRemains(c *fiber.Ctx, user models.User) error {
+	perms, err := s.roleRepo.GetPermissionsByRoleID(c.UserContext(), user.RoleID.String())
+	if err != nil {
+		return err
+	}
+	return helpers.ValidateAdminPermissionRemains(c.UserContext(), s.roleRepo, perms, "", user.ID.String())
+}
+
+// DeleteUser menerima ?mode:
+//   - soft (default): user dinonaktifkan dan ditandai terhapus, seluruh data tetap ada dan dapat dipulihkan
+//   - hard: user dihapus permanen, ditolak dengan USER_HAS_DEPENDENCIES selama masih ada data terkait
+//   - anonymize: data pribadi dihapus permanen, prestasi terverifikasi tetap tersimpan untuk statistik
 func (s *userService) DeleteUser(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	userID, err := uuid.Parse(idParam)
+	userID, err := helpers.ParseUUIDParam(c, "id")
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format ID tidak valid",
-			"success": false,
-		})
-	}
-
-	err = repository.DeleteUser(s.db, userID)
-	if err == sql.ErrNoRows {
-		return c.Status(404).JSON(fiber.Map{
-			"message": "User tidak ditemukan",
-			"success": false,
-		})
-	} else if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal menghapus data user",
-			"success": false,
-			"error":   err.Error(),
-		})
+		return err
 	}
 
+	mode, err := helpers.ParseEnumQuery(c, "mode", models.UserDeleteModes)
+	if err != nil {
+		return err
+	}
+	if mode == "" {
+		mode = models.DeleteSoft
+	}
+
+	existing, err := s.userRepo.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+	if existing.AnonymizedAt != nil {
+		return apperror.Conflict(apperror.CodeUserAnonymized)
+	}
+	// Berlaku untuk semua mode: soft delete, hard delete dan anonimisasi sama-sama menghilangkan pemegang permission
+	if err := s.ensureAdminPermissionRemains(c, existing); err != nil {
+		return err
+	}
+
+	// Sesi aktif diambil lebih dulu karena hard delete ikut menghapus barisnya
+	sessions, err := s.sessionRepo.GetActiveSessionsByUserID(c.UserContext(), userID.String())
+	if err != nil {
+		return err
+	}
+
+	message := i18n.MsgUserDeleted
+	switch mode {
+	case models.DeleteHard:
+		err = s.userRepo.DeleteUser(c.UserContext(), userID)
+	case models.DeleteAnonymize:
+		message = i18n.MsgUserAnonymized
+		// Entry audit ditulis repository di transaksi anonimisasi bersama redaksi audit log lama
+		entry := helpers.NewAuditEntry(c, models.AuditUserAnonymize, "user", userID.String(), nil, fiber.Map{"mode": mode})
+		err = s.userRepo.AnonymizeUser(c.UserContext(), userID, entry)
+	default:
+		err = s.userRepo.SoftDeleteUser(c.UserContext(), userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if mode != models.DeleteHard {
+		actorID := c.Locals("user_id").(uuid.UUID)
+		if _, err := s.sessionRepo.RevokeSessionsByUserID(c.UserContext(), userID.String(), actorID.String()); err != nil {
+			return err
+		}
+	}
+	for _, session := range sessions {
+		utils.DenySession(session.ID.String())
+	}
+
+	if mode != models.DeleteAnonymize {
+		helpers.RecordAudit(c, s.auditRepo, models.AuditUserDelete, "user", userID.String(), existing, fiber.Map{"mode": mode})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, message),
+		"success": true,
+		"data": fiber.Map{
+			"id":   userID.String(),
+			"mode": mode,
+		},
+	})
+}
+
+// RestoreUser memulihkan user yang di-soft delete; user tetap perlu login ulang karena sesinya sudah dicabut
+func (s *userService) RestoreUser(c *fiber.Ctx) error {
+	userID, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.userRepo.GetUserByID(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+	if existing.AnonymizedAt != nil {
+		return apperror.Conflict(apperror.CodeUserAnonymized)
+	}
+
+	if err := s.userRepo.RestoreUser(c.UserContext(), userID); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditUserRestore, "user", userID.String(),
+		fiber.Map{"deleted_at": existing.DeletedAt}, fiber.Map{"is_active": true})
+
 	return c.JSON(fiber.Map{
-		"message": "User berhasil dihapus",
+		"message": helpers.Message(c, i18n.MsgUserRestored),
 		"success": true,
 	})
 }
 
 func (s *userService) UpdateUserRole(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	userID, err := uuid.Parse(idParam)
+	userID, err := helpers.ParseUUIDParam(c, "id")
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format User ID tidak valid",
-			"success": false,
-		})
+		return err
 	}
 
 	var req models.UpdateRole
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format JSON tidak valid",
-			"success": false,
-		})
+	if err := helpers.ParseAndValidate(c, &req); err != nil {
+		return err
 	}
+	roleID := uuid.MustParse(req.RoleID)
 
-	roleID, err := uuid.Parse(req.RoleID)
+	user, err := s.userRepo.GetUserByID(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"message": "Format Role ID tidak valid",
-			"success": false,
-		})
-	}
-
-	err = repository.UpdateUserRole(s.db, userID, roleID)
-	if err == sql.ErrNoRows {
-		return c.Status(404).JSON(fiber.Map{
-			"message": "User tidak ditemukan",
-			"success": false,
-		})
-	} else if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"message": "Gagal update role user",
-			"success": false,
-			"error":   err.Error(),
-		})
+		return err
+	}
+
+	if _, err := s.roleRepo.GetRoleByID(c.UserContext(), roleID.String()); err != nil {
+		return err
 	}
 
+	// Permission yang hilang dari user karena pindah role
+	currentPerms, err := s.roleRepo.GetPermissionsByRoleID(c.UserContext(), user.RoleID.String())
+	if err != nil {
+		return err
+	}
+	newPerms, err := s.roleRepo.GetPermissionsByRoleID(c.UserContext(), roleID.String())
+	if err != nil {
+		return err
+	}
+
+	var lostPerms []models.Permission
+	for _, cp := range currentPerms {
+		kept := false
+		for _, np := range newPerms {
+			if np.ID == cp.ID {
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			lostPerms = append(lostPerms, cp)
+		}
+	}
+
+	if user.IsActive {
+		if err := helpers.ValidateAdminPermissionRemains(c.UserContext(), s.roleRepo, lostPerms, "", userID.String()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userRepo.UpdateUserRole(c.UserContext(), userID, roleID); err != nil {
+		return err
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditUserRoleUpdate, "user", userID.String(),
+		fiber.Map{"role_id": user.RoleID}, fiber.Map{"role_id": roleID})
+
 	return c.JSON(fiber.Map{
-		"message": "Role user berhasil diperbarui",
+		"message": helpers.Message(c, i18n.MsgUserRoleUpdated),
 		"success": true,
 	})
-}
\ No newline at end of file
+}
+
+func (s *userService) ImpersonateUser(c *fiber.Ctx) error {
+	targetID, err := helpers.ParseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req models.ImpersonateRequest
+	if len(c.Body()) > 0 {
+		if err := helpers.ParseAndValidate(c, &req); err != nil {
+			return err
+		}
+	}
+
+	// Batas maksimal 60 menit dijaga oleh tag validate pada ImpersonateRequest
+	if req.DurationMinutes == 0 {
+		req.DurationMinutes = defaultImpersonationMinutes
+	}
+
+	adminID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return apperror.Unauthorized(apperror.CodeUnauthorized)
+	}
+	adminUsername, _ := c.Locals("username").(string)
+
+	if adminID == targetID {
+		return apperror.BadRequest(apperror.CodeImpersonateSelf)
+	}
+
+	target, err := s.userRepo.GetUserByID(c.UserContext(), targetID)
+	if err != nil {
+		return err
+	}
+
+	if !target.IsActive {
+		return apperror.Forbidden(apperror.CodeImpersonateInactive)
+	}
+
+	// Impersonasi user berhak administratif (termasuk role kustom) hanya akan menyamarkan aksi administratif
+	if target.RoleName == models.RoleAdmin {
+		return apperror.Forbidden(apperror.CodeImpersonateAdmin)
+	}
+	perms, err := s.roleRepo.GetPermissionsByRoleID(c.UserContext(), target.RoleID.String())
+	if err != nil {
+		return err
+	}
+	if perm := helpers.PrivilegedPermission(perms); perm != "" {
+		return apperror.Forbidden(apperror.CodeImpersonateAdmin).With("permission", perm)
+	}
+
+	// Impersonasi dicatat sebagai sesi milik user target sehingga ikut dicabut oleh force logout,
+	// penghapusan user, maupun oleh user target sendiri lewat daftar sesinya
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	session := models.Session{
+		ID:        uuid.New(),
+		UserID:    target.ID,
+		Device:    "Impersonasi oleh " + adminUsername,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(duration),
+	}
+	if err := s.sessionRepo.CreateSession(c.UserContext(), session); err != nil {
+		return err
+	}
+
+	actor := models.ActorClaim{UserID: adminID, Username: adminUsername}
+	token, expiresAt, err := utils.GenerateImpersonationToken(target, actor, session.ID.String(), duration)
+	if err != nil {
+		return apperror.Internal(fmt.Errorf("gagal generate token impersonasi: %w", err))
+	}
+
+	helpers.RecordAudit(c, s.auditRepo, models.AuditUserImpersonate, "user", targetID.String(), nil,
+		fiber.Map{"duration_minutes": req.DurationMinutes, "reason": req.Reason, "expires_at": expiresAt})
+
+	return c.JSON(fiber.Map{
+		"message": helpers.Message(c, i18n.MsgUserImpersonationCreated),
+		"success": true,
+		"data": fiber.Map{
+			"token":      token,
+			"expires_at": expiresAt,
+			"user": models.UserResponseDTO{
+				ID:       target.ID,
+				Username: target.Username,
+				FullName: target.FullName,
+				Role:     target.RoleName,
+			},
+			"act": actor,
+		},
+	})
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"uas/config"
+	"uas/database"
+	"uas/database/seeder"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateUsage = `penggunaan: uas migrate [-target postgres|mongo] <perintah>
+
+perintah:
+  up            menerapkan seluruh migration yang belum dijalankan
+  down [N]      membatalkan N migration terakhir (default 1)
+  version       menampilkan versi migration saat ini
+  force V       menandai versi V sebagai bersih (setelah memperbaiki migration dirty)`
+
+func runCommand(ctx context.Context, cfg *config.Config, name string, args []string) error {
+	switch name {
+	case "migrate":
+		return runMigrate(ctx, cfg, args)
+	case "seed":
+		return runSeed(ctx, cfg, args)
+	case "repair":
+		return runRepair(ctx, cfg, args)
+	default:
+		return fmt.Errorf("subcommand tidak dikenal: %q (tersedia: migrate, seed, repair)", name)
+	}
+}
+
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	target := flags.String("target", database.MigrateTargetPostgres, "database yang dimigrasi: postgres atau mongo")
+	flags.Usage = func() { fmt.Fprintln(flags.Output(), migrateUsage) }
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("perintah migrate wajib diisi")
+	}
+
+	var (
+		m   *migrate.Migrate
+		err error
+	)
+	switch *target {
+	case database.MigrateTargetPostgres:
+		db, connErr := connectPostgres(ctx, cfg)
+		if connErr != nil {
+			return connErr
+		}
+		defer db.Close()
+		m, err = database.NewPostgresMigrator(ctx, db)
+	case database.MigrateTargetMongo:
+		m, err = database.NewMongoMigrator(ctx, cfg.Mongo)
+	default:
+		return fmt.Errorf("target migration tidak dikenal: %q", *target)
+	}
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		err = m.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("jumlah langkah down tidak valid: %q", args[1])
+			}
+		}
+		err = m.Steps(-steps)
+	case "version":
+	case "force":
+		if len(args) < 2 {
+			return errors.New("penggunaan: uas migrate force V")
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			return fmt.Errorf("versi tidak valid: %q", args[1])
+		}
+		err = m.Force(version)
+	default:
+		flags.Usage()
+		return fmt.Errorf("perintah migrate tidak dikenal: %q", args[0])
+	}
+
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate %s (%s) gagal: %w", args[0], *target, err)
+	}
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		slog.Info("belum ada migration yang dijalankan", "target", *target)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	slog.Info("versi migration", "target", *target, "version", version, "dirty", dirty)
+	return nil
+}
+
+// runSeed menerapkan file seed dengan profil tertentu. Admin pertama (profil prod)
+// dibaca dari SEED_ADMIN_USERNAME, SEED_ADMIN_EMAIL, SEED_ADMIN_PASSWORD dan SEED_ADMIN_FULL_NAME.
+func runSeed(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	profile := flags.String("profile", "prod", "profil seed: prod atau demo")
+	path := flags.String("file", seeder.DefaultPath, "lokasi file seed (YAML/JSON)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	file, err := seeder.Load(*path)
+	if err != nil {
+		return err
+	}
+
+	db, err := connectPostgres(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	mongoDB, err := database.ConnectMongoDB(ctx, cfg.Mongo)
+	if err != nil {
+		return err
+	}
+	defer mongoDB.Client().Disconnect(context.Background())
+
+	admin := &seeder.Admin{
+		Username: os.Getenv("SEED_ADMIN_USERNAME"),
+		Email:    os.Getenv("SEED_ADMIN_EMAIL"),
+		Password: os.Getenv("SEED_ADMIN_PASSWORD"),
+		FullName: os.Getenv("SEED_ADMIN_FULL_NAME"),
+	}
+
+	report, err := seeder.New(db, mongoDB).Run(ctx, file, *profile, admin)
+	if err != nil {
+		return fmt.Errorf("seed profil %s gagal: %w", *profile, err)
+	}
+
+	slog.Info("seed selesai", "profile", *profile,
+		"permissions", report.Permissions, "roles", report.Roles, "grants", report.Grants,
+		"users", report.Users, "achievements", report.Achievements)
+	return nil
+}
+
+const repairUsage = `penggunaan: uas repair [-apply] <perbaikan>
+
+perbaikan:
+  achievement-deleted-at   memulihkan prestasi yang tersembunyi karena default deleted_at lama (lihat migration 000018)
+
+tanpa -apply hanya menampilkan baris yang akan diperbaiki`
+
+// runRepair menjalankan perbaikan data satu kali yang sengaja tidak dijalankan otomatis oleh migration
+func runRepair(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("repair", flag.ContinueOnError)
+	apply := flags.Bool("apply", false, "simpan perbaikan (default hanya dry run)")
+	flags.Usage = func() { fmt.Fprintln(flags.Output(), repairUsage) }
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return errors.New("jenis perbaikan wajib diisi")
+	}
+	if args[0] != "achievement-deleted-at" {
+		flags.Usage()
+		return fmt.Errorf("perbaikan tidak dikenal: %q", args[0])
+	}
+
+	db, err := connectPostgres(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	mongoDB, err := database.ConnectMongoDB(ctx, cfg.Mongo)
+	if err != nil {
+		return err
+	}
+	defer mongoDB.Client().Disconnect(context.Background())
+
+	ids, err := database.RepairAchievementDeletedAt(ctx, db, mongoDB, *apply)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		slog.Info("referensi prestasi tersembunyi", "achievement_id", id)
+	}
+	slog.Info("repair selesai", "repair", args[0], "rows", len(ids), "applied", *apply)
+	return nil
+}
@@ -1,14 +1,292 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-func Config() {
-	err := godotenv.Load()
-	if err != nil{
-		log.Fatal("Error loading .env ", err)
+// Versi build, diisi saat kompilasi:
+// go build -ldflags "-X uas/config.Version=v1.2.3"
+var Version = "dev"
+
+// Panjang minimal JWT_SECRET agar HS256 tidak mudah di-bruteforce
+const MinJWTSecretLength = 32
+
+type Config struct {
+	App      AppConfig
+	Postgres PostgresConfig
+	Mongo    MongoConfig
+	JWT      JWTConfig
+	Log      LogConfig
+	Tracing  TracingConfig
+}
+
+type AppConfig struct {
+	Name string
+	Env  string
+	Port int
+
+	// Lifecycle: batas waktu drain request saat shutdown dan retry koneksi database saat startup
+	ShutdownTimeout   time.Duration
+	StartupRetries    int
+	StartupRetryDelay time.Duration
+
+	// Menjalankan migration yang di-embed saat startup
+	AutoMigrate bool
+}
+
+type PostgresConfig struct {
+	URI             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+type MongoConfig struct {
+	URI            string
+	Database       string
+	ConnectTimeout time.Duration
+}
+
+type JWTConfig struct {
+	Secret     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+type LogConfig struct {
+	Level  string // debug, info, warn, error
+	Format string // json atau text
+}
+
+// Exporter tracing yang didukung
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
+type TracingConfig struct {
+	Exporter    string  // none, stdout atau otlp
+	Endpoint    string  // host:port collector OTLP/HTTP, kosong = default exporter (localhost:4318)
+	Insecure    bool    // OTLP tanpa TLS
+	SampleRatio float64 // 0-1, proporsi trace baru yang direkam
+	ServiceName string
+}
+
+// Addr mengembalikan alamat listen server, contoh ":3000"
+func (c AppConfig) Addr() string {
+	return ":" + strconv.Itoa(c.Port)
+}
+
+// ValidationError berisi seluruh masalah konfigurasi sekaligus, bukan hanya yang pertama
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "konfigurasi tidak valid:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load membaca konfigurasi dengan urutan prioritas: environment variable > file > default.
+// Jika path kosong, CONFIG_FILE dipakai, dan jika itu juga kosong file .env dibaca bila ada.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+
+	if path != "" {
+		if err := godotenv.Load(path); err != nil {
+			return nil, fmt.Errorf("gagal membaca file konfigurasi %s: %w", path, err)
+		}
+	} else if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("gagal membaca file .env: %w", err)
+	}
+
+	return FromEnv(os.Getenv)
+}
+
+// FromEnv menyusun Config dari fungsi lookup environment lalu memvalidasinya
+func FromEnv(getenv func(string) string) (*Config, error) {
+	r := envReader{getenv: getenv}
+
+	cfg := &Config{
+		App: AppConfig{
+			Name: r.string("APP_NAME", "Sistem Pelaporan Prestasi Mahasiswa"),
+			Env:  r.string("APP_ENV", "development"),
+			Port: r.int("APP_PORT", 3000),
+
+			ShutdownTimeout:   r.duration("APP_SHUTDOWN_TIMEOUT", 15*time.Second),
+			StartupRetries:    r.int("APP_STARTUP_RETRIES", 5),
+			StartupRetryDelay: r.duration("APP_STARTUP_RETRY_DELAY", 2*time.Second),
+			AutoMigrate:       r.bool("APP_AUTO_MIGRATE", false),
+		},
+		Postgres: PostgresConfig{
+			URI:             r.string("POSTGRES_URI", getenv("POSGRES_URI")),
+			MaxOpenConns:    r.int("POSTGRES_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    r.int("POSTGRES_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime: r.duration("POSTGRES_CONN_MAX_LIFETIME", 30*time.Minute),
+		},
+		Mongo: MongoConfig{
+			URI:            r.string("MONGO_URI", ""),
+			Database:       r.string("MONGO_DB", "uas"),
+			ConnectTimeout: r.duration("MONGO_CONNECT_TIMEOUT", 10*time.Second),
+		},
+		JWT: JWTConfig{
+			Secret:     r.string("JWT_SECRET", ""),
+			AccessTTL:  r.duration("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTTL: r.duration("JWT_REFRESH_TTL", 7*24*time.Hour),
+		},
+		Tracing: TracingConfig{
+			Exporter:    strings.ToLower(r.string("TRACING_EXPORTER", TracingExporterNone)),
+			Endpoint:    r.string("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			Insecure:    r.bool("OTEL_EXPORTER_OTLP_INSECURE", false),
+			SampleRatio: r.float("TRACING_SAMPLE_RATIO", 1),
+			ServiceName: r.string("OTEL_SERVICE_NAME", "uas"),
+		},
+		Log: LogConfig{
+			Level:  strings.ToLower(r.string("LOG_LEVEL", "info")),
+			Format: strings.ToLower(r.string("LOG_FORMAT", "json")),
+		},
+	}
+
+	problems := append(r.problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) validate() []string {
+	var problems []string
+
+	if c.App.Port < 1 || c.App.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("APP_PORT harus di antara 1-65535 (saat ini %d)", c.App.Port))
+	}
+	if c.App.ShutdownTimeout <= 0 {
+		problems = append(problems, "APP_SHUTDOWN_TIMEOUT harus lebih dari 0")
+	}
+	if c.App.StartupRetries < 1 {
+		problems = append(problems, "APP_STARTUP_RETRIES minimal 1")
+	}
+	if c.App.StartupRetryDelay < 0 {
+		problems = append(problems, "APP_STARTUP_RETRY_DELAY tidak boleh negatif")
+	}
+	if c.Postgres.URI == "" {
+		problems = append(problems, "POSTGRES_URI wajib diisi")
+	}
+	if c.Postgres.MaxOpenConns < 1 {
+		problems = append(problems, "POSTGRES_MAX_OPEN_CONNS minimal 1")
+	}
+	if c.Postgres.MaxIdleConns < 0 || c.Postgres.MaxIdleConns > c.Postgres.MaxOpenConns {
+		problems = append(problems, "POSTGRES_MAX_IDLE_CONNS harus di antara 0 dan POSTGRES_MAX_OPEN_CONNS")
+	}
+	if c.Mongo.URI == "" {
+		problems = append(problems, "MONGO_URI wajib diisi")
+	}
+	if c.Mongo.Database == "" {
+		problems = append(problems, "MONGO_DB wajib diisi")
+	}
+	if len(c.JWT.Secret) < MinJWTSecretLength {
+		problems = append(problems, fmt.Sprintf("JWT_SECRET minimal %d karakter (saat ini %d)", MinJWTSecretLength, len(c.JWT.Secret)))
+	}
+	if c.JWT.AccessTTL <= 0 || c.JWT.RefreshTTL <= 0 {
+		problems = append(problems, "JWT_ACCESS_TTL dan JWT_REFRESH_TTL harus lebih dari 0")
+	} else if c.JWT.AccessTTL >= c.JWT.RefreshTTL {
+		problems = append(problems, "JWT_ACCESS_TTL harus lebih pendek dari JWT_REFRESH_TTL")
+	}
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL harus salah satu dari debug, info, warn, error (saat ini %q)", c.Log.Level))
+	}
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+	default:
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER harus none, stdout atau otlp (saat ini %q)", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "TRACING_SAMPLE_RATIO harus di antara 0 dan 1")
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		problems = append(problems, fmt.Sprintf("LOG_FORMAT harus json atau text (saat ini %q)", c.Log.Format))
+	}
+
+	return problems
+}
+
+// envReader membaca environment variable dan mengumpulkan error parsing
+type envReader struct {
+	getenv   func(string) string
+	problems []string
+}
+
+func (r *envReader) string(key string, def string) string {
+	if v := strings.TrimSpace(r.getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+func (r *envReader) int(key string, def int) int {
+	v := strings.TrimSpace(r.getenv(key))
+	if v == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s harus berupa angka (saat ini %q)", key, v))
+		return def
+	}
+	return n
+}
+
+func (r *envReader) bool(key string, def bool) bool {
+	v := strings.TrimSpace(r.getenv(key))
+	if v == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s harus berupa true/false (saat ini %q)", key, v))
+		return def
+	}
+	return b
+}
+
+func (r *envReader) float(key string, def float64) float64 {
+	v := strings.TrimSpace(r.getenv(key))
+	if v == "" {
+		return def
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s harus berupa angka desimal (saat ini %q)", key, v))
+		return def
+	}
+	return f
+}
+
+func (r *envReader) duration(key string, def time.Duration) time.Duration {
+	v := strings.TrimSpace(r.getenv(key))
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s harus berupa durasi seperti 15m atau 24h (saat ini %q)", key, v))
+		return def
 	}
-}
\ No newline at end of file
+	return d
+}
@@ -1,14 +0,0 @@
-package config
-
-import (
-	"log"
-
-	"github.com/joho/godotenv"
-)
-
-func ConfigTest() {
-	err := godotenv.Load("../.env")
-	if err != nil{
-		log.Fatal("Error loading .env ", err)
-	}
-}
\ No newline at end of file
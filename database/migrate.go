@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"uas/config"
+	"uas/database/migrations"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/mongodb"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Target migration yang didukung
+const (
+	MigrateTargetPostgres = "postgres"
+	MigrateTargetMongo    = "mongo"
+)
+
+// NewPostgresMigrator membuat migrator dari migration SQL yang di-embed.
+// Migrator memakai satu koneksi dari pool, sehingga Close() tidak menutup pool aplikasi.
+func NewPostgresMigrator(ctx context.Context, db *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.Postgres, ".")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca migration PostgreSQL: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil koneksi PostgreSQL: %w", err)
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("gagal menyiapkan driver migration PostgreSQL: %w", err)
+	}
+
+	return migrate.NewWithInstance("iofs", source, MigrateTargetPostgres, driver)
+}
+
+// NewMongoMigrator membuat migrator index/collection MongoDB.
+// Driver golang-migrate memutus client saat Close(), jadi migrator memakai client tersendiri.
+func NewMongoMigrator(ctx context.Context, cfg config.MongoConfig) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.Mongo, "mongo")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca migration MongoDB: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI))
+	if err != nil {
+		return nil, fmt.Errorf("gagal koneksi ke database MongoDB: %w", err)
+	}
+
+	driver, err := mongodb.WithInstance(client, &mongodb.Config{DatabaseName: cfg.Database})
+	if err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("gagal menyiapkan driver migration MongoDB: %w", err)
+	}
+
+	return migrate.NewWithInstance("iofs", source, MigrateTargetMongo, driver)
+}
+
+// MigrateUp menjalankan seluruh migration PostgreSQL lalu MongoDB yang belum diterapkan
+func MigrateUp(ctx context.Context, db *sql.DB, cfg config.MongoConfig) error {
+	pg, err := NewPostgresMigrator(ctx, db)
+	if err != nil {
+		return err
+	}
+	if err := runUp(MigrateTargetPostgres, pg); err != nil {
+		return err
+	}
+
+	mg, err := NewMongoMigrator(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return runUp(MigrateTargetMongo, mg)
+}
+
+func runUp(target string, m *migrate.Migrate) error {
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migration %s gagal: %w", target, err)
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("gagal membaca versi migration %s: %w", target, err)
+	}
+
+	slog.Info("migration selesai", "target", target, "version", version, "dirty", dirty)
+	return nil
+}
@@ -5,6 +5,6 @@ CREATE TABLE students (
 	program_study VARCHAR(100),
 	academy_year VARCHAR(10),
 	advisor_id UUID REFERENCES lecturers(id),
-	created_at TIMESTAMP DEFAULT NOW()
+	created_at TIMESTAMP DEFAULT NOW(),
 	updated_at TIMESTAMP DEFAULT NOW()
 );
\ No newline at end of file
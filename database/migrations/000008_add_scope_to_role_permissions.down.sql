@@ -0,0 +1,2 @@
+ALTER TABLE role_permissions DROP CONSTRAINT IF EXISTS chk_role_permissions_scope;
+ALTER TABLE role_permissions DROP COLUMN IF EXISTS scope;
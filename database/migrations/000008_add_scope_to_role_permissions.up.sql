@@ -0,0 +1,26 @@
+-- Scope permission: own (milik sendiri), advisees (mahasiswa bimbingan), department, all
+ALTER TABLE role_permissions
+    ADD COLUMN IF NOT EXISTS scope VARCHAR(20) NOT NULL DEFAULT 'all';
+
+ALTER TABLE role_permissions
+    ADD CONSTRAINT chk_role_permissions_scope
+    CHECK (scope IN ('own', 'advisees', 'department', 'all'));
+
+-- Grant yang sudah ada mendapat default 'all'. Tanpa backfill ini mahasiswa dapat mengubah prestasi
+-- mahasiswa lain dan dosen wali dapat memverifikasi seluruh prestasi, karena pengecekan kepemilikan
+-- kini dilakukan lewat scope.
+UPDATE role_permissions rp
+SET scope = 'own'
+FROM roles r, permissions p
+WHERE rp.role_id = r.id
+  AND rp.permission_id = p.id
+  AND r.name = 'Mahasiswa'
+  AND p.name IN ('achievements:create', 'achievements:update', 'achievements:delete');
+
+UPDATE role_permissions rp
+SET scope = 'advisees'
+FROM roles r, permissions p
+WHERE rp.role_id = r.id
+  AND rp.permission_id = p.id
+  AND r.name = 'Dosen Wali'
+  AND p.name IN ('achievements:verify', 'achievements:reject');
@@ -0,0 +1,3 @@
+ALTER TABLE audit_logs
+    DROP COLUMN IF EXISTS impersonator_id,
+    DROP COLUMN IF EXISTS impersonator_username;
@@ -0,0 +1,4 @@
+-- Admin yang sedang melakukan impersonasi (klaim "act" pada JWT)
+ALTER TABLE audit_logs
+    ADD COLUMN IF NOT EXISTS impersonator_id UUID,
+    ADD COLUMN IF NOT EXISTS impersonator_username VARCHAR(50);
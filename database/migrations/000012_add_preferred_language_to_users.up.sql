@@ -0,0 +1,7 @@
+-- Bahasa respons pilihan user (id / en); NULL berarti mengikuti header Accept-Language
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS preferred_language VARCHAR(5);
+
+ALTER TABLE users
+    ADD CONSTRAINT chk_users_preferred_language
+    CHECK (preferred_language IN ('id', 'en'));
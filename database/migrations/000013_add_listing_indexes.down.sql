@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_lecturers_lecturer_id_trgm;
+DROP INDEX IF EXISTS idx_students_student_id_trgm;
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_username_trgm;
+DROP INDEX IF EXISTS idx_users_full_name_trgm;
+
+DROP INDEX IF EXISTS idx_lecturers_department;
+DROP INDEX IF EXISTS idx_lecturers_user_id;
+DROP INDEX IF EXISTS idx_students_academy_year;
+DROP INDEX IF EXISTS idx_students_program_study;
+DROP INDEX IF EXISTS idx_students_advisor_id;
+DROP INDEX IF EXISTS idx_students_user_id;
+DROP INDEX IF EXISTS idx_users_role_id;
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_users_full_name_id;
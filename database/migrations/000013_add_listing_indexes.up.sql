@@ -0,0 +1,20 @@
+-- Index untuk sorting, filter dan pencarian pada daftar user, mahasiswa dan dosen
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Keyset pagination: (kolom sort, id)
+CREATE INDEX IF NOT EXISTS idx_users_full_name_id ON users (full_name, id);
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_role_id ON users (role_id);
+CREATE INDEX IF NOT EXISTS idx_students_user_id ON students (user_id);
+CREATE INDEX IF NOT EXISTS idx_students_advisor_id ON students (advisor_id);
+CREATE INDEX IF NOT EXISTS idx_students_program_study ON students (program_study);
+CREATE INDEX IF NOT EXISTS idx_students_academy_year ON students (academy_year);
+CREATE INDEX IF NOT EXISTS idx_lecturers_user_id ON lecturers (user_id);
+CREATE INDEX IF NOT EXISTS idx_lecturers_department ON lecturers (department);
+
+-- Pencarian sebagian kata (ILIKE '%...%')
+CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm ON users USING gin (full_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING gin (username gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING gin (email gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_students_student_id_trgm ON students USING gin (student_id gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_lecturers_lecturer_id_trgm ON lecturers USING gin (lecturer_id gin_trgm_ops);
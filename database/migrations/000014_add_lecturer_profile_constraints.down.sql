@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_lecturers_user_id;
+DROP INDEX IF EXISTS uq_lecturers_lecturer_id;
+
+ALTER TABLE lecturers DROP COLUMN IF EXISTS updated_at;
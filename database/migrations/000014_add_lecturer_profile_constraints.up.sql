@@ -0,0 +1,6 @@
+-- NIP dan akun user hanya boleh dimiliki satu profil dosen, serta waktu perubahan profil
+ALTER TABLE lecturers
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT NOW();
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_lecturers_lecturer_id ON lecturers (lecturer_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_lecturers_user_id ON lecturers (user_id);
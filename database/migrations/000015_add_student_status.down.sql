@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS student_status_history;
+
+DROP INDEX IF EXISTS idx_students_status;
+ALTER TABLE students DROP CONSTRAINT IF EXISTS chk_students_status;
+ALTER TABLE students DROP COLUMN IF EXISTS status;
@@ -0,0 +1,18 @@
+-- Status studi mahasiswa beserta riwayat perubahannya
+ALTER TABLE students
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active',
+    ADD CONSTRAINT chk_students_status CHECK (status IN ('active', 'on_leave', 'graduated', 'dropped_out'));
+
+CREATE INDEX IF NOT EXISTS idx_students_status ON students (status);
+
+CREATE TABLE IF NOT EXISTS student_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_status_history_student ON student_status_history (student_id, created_at DESC);
@@ -0,0 +1,19 @@
+ALTER TABLE achievement_references
+    DROP CONSTRAINT IF EXISTS fk_achievement_student,
+    ADD CONSTRAINT fk_achievement_student FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE;
+
+ALTER TABLE students
+    DROP CONSTRAINT IF EXISTS students_advisor_id_fkey,
+    ADD CONSTRAINT students_advisor_id_fkey FOREIGN KEY (advisor_id) REFERENCES lecturers(id);
+
+ALTER TABLE lecturers
+    DROP CONSTRAINT IF EXISTS lecturers_user_id_fkey,
+    ADD CONSTRAINT lecturers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+
+ALTER TABLE students
+    DROP CONSTRAINT IF EXISTS students_user_id_fkey,
+    ADD CONSTRAINT students_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS anonymized_at,
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,25 @@
+-- Soft delete & anonimisasi user
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP;
+
+-- Profil mahasiswa/dosen ikut terhapus bersama akunnya. Hard delete tetap dicegah aplikasi
+-- selama masih ada prestasi, mahasiswa bimbingan atau riwayat verifikasi.
+ALTER TABLE students
+    DROP CONSTRAINT IF EXISTS students_user_id_fkey,
+    ADD CONSTRAINT students_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE lecturers
+    DROP CONSTRAINT IF EXISTS lecturers_user_id_fkey,
+    ADD CONSTRAINT lecturers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+-- Pengaman terakhir di database: dosen yang masih memiliki mahasiswa bimbingan dan mahasiswa
+-- yang masih memiliki prestasi tidak dapat terhapus
+ALTER TABLE students
+    DROP CONSTRAINT IF EXISTS students_advisor_id_fkey,
+    ADD CONSTRAINT students_advisor_id_fkey FOREIGN KEY (advisor_id) REFERENCES lecturers(id) ON DELETE RESTRICT;
+
+ALTER TABLE achievement_references
+    DROP CONSTRAINT IF EXISTS fk_achievement_student,
+    ADD CONSTRAINT fk_achievement_student FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE RESTRICT;
+
@@ -0,0 +1,8 @@
+-- Baris yang sudah diredaksi tidak dapat dikembalikan; setelah rollback verifikasi hash chain akan gagal pada baris tersebut
+CREATE OR REPLACE FUNCTION audit_logs_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs bersifat append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS redacted_at;
@@ -0,0 +1 @@
+ALTER TABLE achievement_references ALTER COLUMN deleted_at SET DEFAULT CURRENT_TIMESTAMP;
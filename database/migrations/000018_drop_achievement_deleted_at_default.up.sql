@@ -0,0 +1,3 @@
+-- deleted_at sebelumnya default CURRENT_TIMESTAMP sehingga prestasi baru langsung tersembunyi.
+-- Migration ini hanya memperbaiki default; baris lama diperbaiki lewat `uas repair achievement-deleted-at`.
+ALTER TABLE achievement_references ALTER COLUMN deleted_at DROP DEFAULT;
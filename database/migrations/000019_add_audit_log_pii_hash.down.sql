@@ -0,0 +1,28 @@
+-- Entry yang ditulis dengan pii_hash tidak dapat diverifikasi lagi setelah rollback
+CREATE OR REPLACE FUNCTION audit_logs_immutable() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+       AND NEW.redacted_at IS NOT NULL
+       AND NEW.id = OLD.id
+       AND NEW.actor_id IS NOT DISTINCT FROM OLD.actor_id
+       AND NEW.action = OLD.action
+       AND NEW.target_type = OLD.target_type
+       AND NEW.target_id IS NOT DISTINCT FROM OLD.target_id
+       AND NEW.request_id IS NOT DISTINCT FROM OLD.request_id
+       AND NEW.impersonator_id IS NOT DISTINCT FROM OLD.impersonator_id
+       AND NEW.prev_hash = OLD.prev_hash
+       AND NEW.hash = OLD.hash
+       AND NEW.created_at = OLD.created_at
+       AND (NEW.actor_username IS NULL OR NEW.actor_username IS NOT DISTINCT FROM OLD.actor_username)
+       AND (NEW.impersonator_username IS NULL OR NEW.impersonator_username IS NOT DISTINCT FROM OLD.impersonator_username)
+       AND (NEW.ip IS NULL OR NEW.ip IS NOT DISTINCT FROM OLD.ip)
+       AND (NEW.user_agent IS NULL OR NEW.user_agent IS NOT DISTINCT FROM OLD.user_agent)
+       AND (NEW.before IS NULL OR NEW.before IS NOT DISTINCT FROM OLD.before)
+       AND (NEW.after IS NULL OR NEW.after IS NOT DISTINCT FROM OLD.after) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_logs bersifat append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS pii_hash;
@@ -0,0 +1,15 @@
+// Package migrations menyimpan file migration di dalam binary sehingga
+// subcommand `migrate` dan auto-migrate tidak membutuhkan CLI eksternal.
+package migrations
+
+import "embed"
+
+// Postgres berisi migration SQL (format golang-migrate: NNNNNN_nama.up.sql / .down.sql)
+//
+//go:embed *.sql
+var Postgres embed.FS
+
+// Mongo berisi migration collection & index MongoDB dalam format perintah JSON
+//
+//go:embed mongo/*.json
+var Mongo embed.FS
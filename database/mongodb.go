@@ -3,35 +3,64 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
-	"time"
+	"log/slog"
+	"uas/config"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func ConnectMongoDB() *mongo.Database{
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// ConnectMongoDB mengembalikan database yang dipakai aplikasi.
+// Client-nya dapat diambil lewat Database.Client() untuk Disconnect saat shutdown.
+// opts tambahan (misalnya command monitor untuk metrics) digabung dengan URI dari konfigurasi.
+func ConnectMongoDB(ctx context.Context, cfg config.MongoConfig, opts ...*options.ClientOptions) (*mongo.Database, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
 	defer cancel()
 
-	mongoURI := os.Getenv("MONGO_URI")
-	// Ganti dengan connection string MongoDB Anda
-	clientOptions := options.Client().ApplyURI(mongoURI)
+	clientOptions := options.Client().ApplyURI(cfg.URI)
 
-	client, err := mongo.Connect(ctx, clientOptions)
+	client, err := mongo.Connect(ctx, append([]*options.ClientOptions{clientOptions}, opts...)...)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("gagal koneksi ke database MongoDB: %w", err)
 	}
 
 	// Test connection
-	err = client.Ping(ctx, nil)
-	if err != nil {
-		log.Fatal(err)
+	if err = client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("gagal ping database MongoDB: %w", err)
 	}
 
-	fmt.Println("Berhasil terhubung ke database MongoDB!")
+	slog.Info("berhasil terhubung ke database MongoDB", "database", cfg.Database)
 
 	// Set database
-	return client.Database("uas")
-}
\ No newline at end of file
+	return client.Database(cfg.Database), nil
+}
+
+// ChainCommandMonitors menggabungkan beberapa command monitor, karena client MongoDB
+// hanya menerima satu monitor (misalnya tracing dan metrics sekaligus)
+func ChainCommandMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, e)
+				}
+			}
+		},
+	}
+}
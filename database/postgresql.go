@@ -1,30 +1,39 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"uas/config"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
-func ConnectDB() *sql.DB{
+func ConnectDB(ctx context.Context, cfg config.PostgresConfig) (*sql.DB, error) {
 
-	dsn := os.Getenv("POSGRES_URI")
-
-	// Koneksi database
-	db, err := sql.Open("postgres", dsn)
-
-	if err != nil{
-		log.Fatal("Gagal koneksi ke database",err)
+	// Koneksi database, setiap query tercatat sebagai span OpenTelemetry
+	db, err := otelsql.Open("postgres", cfg.URI,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gagal koneksi ke database PostgreSQL: %w", err)
 	}
 
+	// Pengaturan connection pool
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
 	// Tes Koneksi
-	if err = db.Ping(); err != nil{
-		log.Fatal("Gagal ping database", err)
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("gagal ping database PostgreSQL: %w", err)
 	}
 
-	fmt.Println("Berhasil terhubung ke database PostgreSQL!")
-	return db
-}
\ No newline at end of file
+	slog.Info("berhasil terhubung ke database PostgreSQL")
+	return db, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RepairAchievementDeletedAt mencari prestasi yang tersembunyi karena default deleted_at lama (migration 000007):
+// aplikasi meng-insert achievement_references tanpa deleted_at sehingga terisi waktu insert. Baris dianggap
+// tidak pernah dihapus jika dokumen MongoDB-nya ada dan tidak memiliki deletedAt, karena soft delete prestasi
+// selalu menandai kedua database, serta pemiliknya belum dianonimkan. Tanpa apply hanya daftar ID yang
+// dikembalikan (dry run).
+func RepairAchievementDeletedAt(ctx context.Context, db *sql.DB, mongoDB *mongo.Database, apply bool) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT ar.id::text, ar.mongo_achievement_id
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		JOIN users u ON u.id = s.user_id
+		WHERE ar.deleted_at IS NOT NULL AND u.anonymized_at IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query referensi prestasi: %w", err)
+	}
+	defer rows.Close()
+
+	refByMongoID := map[primitive.ObjectID]string{}
+	var oids []primitive.ObjectID
+	for rows.Next() {
+		var id, mongoID string
+		if err := rows.Scan(&id, &mongoID); err != nil {
+			return nil, fmt.Errorf("gagal scanning referensi prestasi: %w", err)
+		}
+		if oid, err := primitive.ObjectIDFromHex(mongoID); err == nil {
+			refByMongoID[oid] = id
+			oids = append(oids, oid)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi rows: %w", err)
+	}
+
+	ids := []string{}
+	if len(oids) == 0 {
+		return ids, nil
+	}
+
+	cursor, err := mongoDB.Collection("achievements").Find(ctx,
+		bson.M{"_id": bson.M{"$in": oids}, "deletedAt": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("gagal query dokumen prestasi: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("gagal decode dokumen prestasi: %w", err)
+		}
+		ids = append(ids, refByMongoID[doc.ID])
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("error iterasi cursor: %w", err)
+	}
+
+	if !apply || len(ids) == 0 {
+		return ids, nil
+	}
+
+	if _, err := db.ExecContext(ctx, `
+		UPDATE achievement_references SET deleted_at = NULL
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NOT NULL`, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("gagal memulihkan referensi prestasi: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Retry menjalankan fn hingga berhasil atau jumlah percobaan habis.
+// Jeda antar percobaan berlipat dua (delay, 2*delay, ...) dan berhenti lebih awal jika ctx dibatalkan.
+func Retry(ctx context.Context, name string, attempts int, delay time.Duration, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		slog.Warn("dependensi belum tersedia, mencoba lagi",
+			"dependency", name, "attempt", attempt, "max_attempts", attempts, "retry_in", delay.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: dibatalkan saat menunggu retry: %w", name, ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	return fmt.Errorf("%s gagal setelah %d percobaan: %w", name, attempts, err)
+}
@@ -0,0 +1,409 @@
+// Package seeder menerapkan data awal (role, permission, user, prestasi) secara deklaratif
+// dari file YAML/JSON. Setiap langkah bersifat upsert sehingga aman dijalankan berulang kali.
+package seeder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"uas/app/models"
+	"uas/helpers"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// Path default file seed, relatif terhadap root project
+const DefaultPath = "database/seeder/seed.yaml"
+
+type File struct {
+	Permissions []Permission       `yaml:"permissions"`
+	Roles       []Role             `yaml:"roles"`
+	Profiles    map[string]Profile `yaml:"profiles"`
+}
+
+type Permission struct {
+	Name        string `yaml:"name"`
+	Resource    string `yaml:"resource"`
+	Action      string `yaml:"action"`
+	Description string `yaml:"description"`
+}
+
+type Role struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Permissions map[string]string `yaml:"permissions"` // nama permission -> scope
+}
+
+type Profile struct {
+	BootstrapAdmin bool          `yaml:"bootstrap_admin"`
+	Users          []User        `yaml:"users"`
+	Achievements   []Achievement `yaml:"achievements"`
+}
+
+type User struct {
+	Username string    `yaml:"username"`
+	Email    string    `yaml:"email"`
+	Password string    `yaml:"password"`
+	FullName string    `yaml:"full_name"`
+	Role     string    `yaml:"role"`
+	Lecturer *Lecturer `yaml:"lecturer"`
+	Student  *Student  `yaml:"student"`
+}
+
+type Lecturer struct {
+	NIP        string `yaml:"nip"`
+	Department string `yaml:"department"`
+}
+
+type Student struct {
+	NIM          string `yaml:"nim"`
+	ProgramStudy string `yaml:"program_study"`
+	AcademicYear string `yaml:"academic_year"`
+	AdvisorNIP   string `yaml:"advisor_nip"`
+}
+
+type Achievement struct {
+	NIM             string                 `yaml:"nim"`
+	AchievementType string                 `yaml:"achievement_type"`
+	Title           string                 `yaml:"title"`
+	Description     string                 `yaml:"description"`
+	Points          int                    `yaml:"points"`
+	Tags            []string               `yaml:"tags"`
+	Details         map[string]interface{} `yaml:"details"`
+	Status          string                 `yaml:"status"`
+	VerifiedBy      string                 `yaml:"verified_by"` // username dosen
+	RejectionNote   string                 `yaml:"rejection_note"`
+}
+
+// Admin pertama untuk profil production, dibaca dari environment oleh pemanggil
+type Admin struct {
+	Username string
+	Email    string
+	Password string
+	FullName string
+}
+
+// Report merangkum jumlah baris yang diterapkan per jenis data
+type Report struct {
+	Permissions  int
+	Roles        int
+	Grants       int
+	Users        int
+	Achievements int
+}
+
+// Load membaca file seed. YAML adalah superset JSON, sehingga file .json juga dapat dibaca.
+func Load(path string) (*File, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file seed %s: %w", path, err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("format file seed %s tidak valid: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Profile mengembalikan profil dengan nama tertentu beserta daftar profil yang tersedia jika tidak ditemukan
+func (f *File) Profile(name string) (Profile, error) {
+	profile, ok := f.Profiles[name]
+	if !ok {
+		var names []string
+		for n := range f.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Profile{}, fmt.Errorf("profil seed %q tidak ada (tersedia: %s)", name, strings.Join(names, ", "))
+	}
+	return profile, nil
+}
+
+type Seeder struct {
+	pg    *sql.DB
+	mongo *mongo.Database
+}
+
+func New(pg *sql.DB, mongo *mongo.Database) *Seeder {
+	return &Seeder{pg: pg, mongo: mongo}
+}
+
+// Run menerapkan permission, role dan user dalam satu transaksi PostgreSQL,
+// lalu prestasi (MongoDB + referensi PostgreSQL) setelah transaksi berhasil.
+func (s *Seeder) Run(ctx context.Context, file *File, profileName string, admin *Admin) (Report, error) {
+	var report Report
+
+	profile, err := file.Profile(profileName)
+	if err != nil {
+		return report, err
+	}
+
+	users := profile.Users
+	if profile.BootstrapAdmin {
+		if admin == nil || admin.Username == "" || admin.Email == "" || admin.Password == "" {
+			return report, errors.New("profil ini membutuhkan SEED_ADMIN_USERNAME, SEED_ADMIN_EMAIL dan SEED_ADMIN_PASSWORD")
+		}
+		if len(admin.Password) < 8 {
+			return report, errors.New("SEED_ADMIN_PASSWORD minimal 8 karakter")
+		}
+		users = append([]User{{
+			Username: admin.Username,
+			Email:    admin.Email,
+			Password: admin.Password,
+			FullName: admin.FullName,
+			Role:     "Admin",
+		}}, users...)
+	}
+
+	tx, err := s.pg.BeginTx(ctx, nil)
+	if err != nil {
+		return report, fmt.Errorf("gagal memulai transaksi seed: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, p := range file.Permissions {
+		if err := upsertPermission(ctx, tx, p); err != nil {
+			return report, err
+		}
+		report.Permissions++
+	}
+
+	for _, r := range file.Roles {
+		grants, err := upsertRole(ctx, tx, r)
+		if err != nil {
+			return report, err
+		}
+		report.Roles++
+		report.Grants += grants
+	}
+
+	// Dosen lebih dulu agar advisor mahasiswa dapat di-resolve dari NIP
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].Lecturer != nil && users[j].Lecturer == nil
+	})
+	for _, u := range users {
+		if err := upsertUser(ctx, tx, u); err != nil {
+			return report, err
+		}
+		report.Users++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return report, fmt.Errorf("gagal commit transaksi seed: %w", err)
+	}
+
+	for _, a := range profile.Achievements {
+		if err := s.upsertAchievement(ctx, a); err != nil {
+			return report, err
+		}
+		report.Achievements++
+	}
+
+	return report, nil
+}
+
+func upsertPermission(ctx context.Context, tx *sql.Tx, p Permission) error {
+	query := `
+		INSERT INTO permissions (name, resource, action, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE
+		SET resource = EXCLUDED.resource, action = EXCLUDED.action, description = EXCLUDED.description
+	`
+	if _, err := tx.ExecContext(ctx, query, p.Name, p.Resource, p.Action, p.Description); err != nil {
+		return fmt.Errorf("gagal seed permission %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+func upsertRole(ctx context.Context, tx *sql.Tx, r Role) (int, error) {
+	query := `
+		INSERT INTO roles (name, description)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description
+		RETURNING id
+	`
+
+	var roleID string
+	if err := tx.QueryRowContext(ctx, query, r.Name, r.Description).Scan(&roleID); err != nil {
+		return 0, fmt.Errorf("gagal seed role %s: %w", r.Name, err)
+	}
+
+	grantQuery := `
+		INSERT INTO role_permissions (role_id, permission_id, scope)
+		SELECT $1::uuid, id, $3 FROM permissions WHERE name = $2
+		ON CONFLICT (role_id, permission_id) DO UPDATE SET scope = EXCLUDED.scope
+	`
+
+	for perm, scope := range r.Permissions {
+		if !helpers.IsValidScope(scope) {
+			return 0, fmt.Errorf("scope %q untuk %s pada role %s tidak valid", scope, perm, r.Name)
+		}
+
+		result, err := tx.ExecContext(ctx, grantQuery, roleID, perm, scope)
+		if err != nil {
+			return 0, fmt.Errorf("gagal grant %s ke role %s: %w", perm, r.Name, err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return 0, fmt.Errorf("permission %s untuk role %s tidak terdaftar", perm, r.Name)
+		}
+	}
+
+	return len(r.Permissions), nil
+}
+
+// upsertUser membuat user jika belum ada (berdasarkan username). User yang sudah ada
+// diperbarui profilnya, tetapi password-nya tidak ditimpa.
+func upsertUser(ctx context.Context, tx *sql.Tx, u User) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("gagal hash password %s: %w", u.Username, err)
+	}
+
+	fullName := u.FullName
+	if fullName == "" {
+		fullName = u.Username
+	}
+
+	query := `
+		INSERT INTO users (username, email, password_hash, full_name, role_id)
+		VALUES ($1, $2, $3, $4, (SELECT id FROM roles WHERE name = $5))
+		ON CONFLICT (username) DO UPDATE
+		SET email = EXCLUDED.email, full_name = EXCLUDED.full_name, role_id = EXCLUDED.role_id, updated_at = NOW()
+		RETURNING id
+	`
+
+	var userID string
+	if err := tx.QueryRowContext(ctx, query, u.Username, u.Email, string(hash), fullName, u.Role).Scan(&userID); err != nil {
+		return fmt.Errorf("gagal seed user %s: %w", u.Username, err)
+	}
+
+	if u.Lecturer != nil {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE lecturers SET lecturer_id = $2, department = $3 WHERE user_id = $1`,
+			userID, u.Lecturer.NIP, u.Lecturer.Department)
+		if err != nil {
+			return fmt.Errorf("gagal seed dosen %s: %w", u.Username, err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO lecturers (user_id, lecturer_id, department) VALUES ($1, $2, $3)`,
+				userID, u.Lecturer.NIP, u.Lecturer.Department)
+			if err != nil {
+				return fmt.Errorf("gagal seed dosen %s: %w", u.Username, err)
+			}
+		}
+	}
+
+	if u.Student != nil {
+		var advisorID sql.NullString
+		if u.Student.AdvisorNIP != "" {
+			err := tx.QueryRowContext(ctx, `SELECT id FROM lecturers WHERE lecturer_id = $1`, u.Student.AdvisorNIP).Scan(&advisorID)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("dosen wali dengan NIP %s untuk %s tidak ditemukan", u.Student.AdvisorNIP, u.Username)
+			} else if err != nil {
+				return err
+			}
+		}
+
+		query := `
+			INSERT INTO students (user_id, student_id, program_study, academy_year, advisor_id)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (student_id) DO UPDATE
+			SET user_id = EXCLUDED.user_id, program_study = EXCLUDED.program_study,
+			    academy_year = EXCLUDED.academy_year, advisor_id = EXCLUDED.advisor_id
+		`
+		_, err := tx.ExecContext(ctx, query, userID, u.Student.NIM, u.Student.ProgramStudy, u.Student.AcademicYear, advisorID)
+		if err != nil {
+			return fmt.Errorf("gagal seed mahasiswa %s: %w", u.Username, err)
+		}
+	}
+
+	return nil
+}
+
+// upsertAchievement memakai (studentId, title) sebagai kunci alami dokumen MongoDB,
+// lalu memastikan referensi PostgreSQL-nya ada dengan status yang diminta.
+func (s *Seeder) upsertAchievement(ctx context.Context, a Achievement) error {
+	var studentID string
+	err := s.pg.QueryRowContext(ctx, `SELECT id FROM students WHERE student_id = $1`, a.NIM).Scan(&studentID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("mahasiswa dengan NIM %s untuk prestasi %q tidak ditemukan", a.NIM, a.Title)
+	} else if err != nil {
+		return err
+	}
+
+	status := a.Status
+	if status == "" {
+		status = "draft"
+	}
+
+	now := time.Now()
+	collection := s.mongo.Collection("achievements")
+	filter := bson.M{"studentId": studentID, "title": a.Title}
+
+	var existing models.AchievementMongo
+	err = collection.FindOne(ctx, filter).Decode(&existing)
+	switch {
+	case err == mongo.ErrNoDocuments:
+		doc := models.AchievementMongo{
+			StudentID:       studentID,
+			AchievementType: a.AchievementType,
+			Title:           a.Title,
+			Description:     a.Description,
+			Details:         a.Details,
+			Tags:            a.Tags,
+			Points:          a.Points,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		result, err := collection.InsertOne(ctx, doc)
+		if err != nil {
+			return fmt.Errorf("gagal seed prestasi %q ke mongo: %w", a.Title, err)
+		}
+		existing.ID = result.InsertedID.(primitive.ObjectID)
+	case err != nil:
+		return fmt.Errorf("gagal membaca prestasi %q dari mongo: %w", a.Title, err)
+	}
+
+	var verifiedBy sql.NullString
+	if a.VerifiedBy != "" {
+		err := s.pg.QueryRowContext(ctx, `SELECT id FROM users WHERE username = $1`, a.VerifiedBy).Scan(&verifiedBy)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("verifikator %s untuk prestasi %q tidak ditemukan", a.VerifiedBy, a.Title)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	var submittedAt, verifiedAt *time.Time
+	if status != "draft" {
+		submittedAt = &now
+	}
+	if status == "verified" || status == "rejected" {
+		verifiedAt = &now
+	}
+
+	query := `
+		INSERT INTO achievement_references (
+			student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, rejection_note, deleted_at
+		)
+		SELECT $1::uuid, $2, $3::achievement_status_enum, $4::timestamp, $5::timestamp, $6::uuid, NULLIF($7, ''), NULL
+		WHERE NOT EXISTS (SELECT 1 FROM achievement_references WHERE mongo_achievement_id = $2)
+	`
+	_, err = s.pg.ExecContext(ctx, query, studentID, existing.ID.Hex(), status, submittedAt, verifiedAt, verifiedBy, a.RejectionNote)
+	if err != nil {
+		return fmt.Errorf("gagal seed referensi prestasi %q: %w", a.Title, err)
+	}
+
+	return nil
+}
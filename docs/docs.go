@@ -0,0 +1,40 @@
+// Package docs menyajikan dokumen OpenAPI API ini beserta halaman dokumentasinya.
+// Dokumen ditulis manual di openapi.yaml; kelengkapannya terhadap routes dicek oleh test/openapi_test.go.
+package docs
+
+import (
+	_ "embed"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Path dokumen OpenAPI yang dirujuk halaman dokumentasi
+const SpecPath = "/api/docs/openapi.yaml"
+
+//go:embed openapi.yaml
+var Spec []byte
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>API Docs - Sistem Pelaporan Prestasi Mahasiswa</title>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="` + SpecPath + `"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>`
+
+// SpecHandler mengirim dokumen OpenAPI apa adanya
+func SpecHandler(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "application/yaml; charset=utf-8")
+	return c.Send(Spec)
+}
+
+// UIHandler mengirim halaman dokumentasi yang merender dokumen OpenAPI
+func UIHandler(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(page)
+}
@@ -0,0 +1,124 @@
+package helpers
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RecordAudit mencatat aksi ke audit log beserta actor, IP, user agent dan request ID.
+// before/after cukup diisi snapshot data; hanya field yang berubah yang disimpan.
+func RecordAudit(c *fiber.Ctx, repo repository.AuditRepository, action string, targetType string, targetID string, before interface{}, after interface{}) {
+	entry := NewAuditEntry(c, action, targetType, targetID, before, after)
+
+	// Audit log tidak boleh menggagalkan aksi yang sudah berhasil, cukup dicatat di log server
+	if err := repo.Append(c.UserContext(), entry); err != nil {
+		utils.Logger(c.UserContext()).Error("gagal menulis audit log",
+			"action", action, "target_type", targetType, "target_id", targetID, "error", err)
+	}
+}
+
+// NewAuditEntry menyusun entry audit log dari request tanpa menyimpannya, untuk repository yang
+// harus menulis audit log di dalam transaksinya sendiri
+func NewAuditEntry(c *fiber.Ctx, action string, targetType string, targetID string, before interface{}, after interface{}) models.AuditLog {
+	entry := models.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		RequestID:  requestID(c),
+		CreatedAt:  time.Now(),
+	}
+
+	if actorID, err := GetUserIDFromContext(c); err == nil {
+		entry.ActorID = actorID
+	}
+	if username, ok := c.Locals("username").(string); ok {
+		entry.ActorUsername = username
+	}
+	if actor, ok := c.Locals("actor").(*models.ActorClaim); ok && actor != nil {
+		entry.ImpersonatorID = actor.UserID.String()
+		entry.ImpersonatorUsername = actor.Username
+	}
+
+	entry.Before, entry.After = DiffAudit(before, after)
+	return entry
+}
+
+// RecordExportAudit mencatat export data massal beserta format, kolom dan filter yang dipakai
+func RecordExportAudit(c *fiber.Ctx, repo repository.AuditRepository, targetType string, opts ExportOptions) {
+	RecordAudit(c, repo, models.AuditDataExport, targetType, "", nil, map[string]interface{}{
+		"format":   opts.Format,
+		"columns":  opts.Columns,
+		"mask_pii": opts.MaskPII,
+		"query":    string(c.Request().URI().QueryString()),
+	})
+}
+
+// requestID diambil dari middleware RequestID, atau langsung dari header jika middleware tidak dipasang
+func requestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals("request_id").(string); ok {
+		return id
+	}
+	return c.Get(fiber.HeaderXRequestID)
+}
+
+// DiffAudit membandingkan dua snapshot dan hanya mengembalikan field yang berbeda
+func DiffAudit(before interface{}, after interface{}) (json.RawMessage, json.RawMessage) {
+	beforeMap := toAuditMap(before)
+	afterMap := toAuditMap(after)
+
+	if beforeMap == nil || afterMap == nil {
+		return marshalAuditMap(beforeMap), marshalAuditMap(afterMap)
+	}
+
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for k, v := range beforeMap {
+		if av, ok := afterMap[k]; !ok || !reflect.DeepEqual(v, av) {
+			changedBefore[k] = v
+		}
+	}
+	for k, v := range afterMap {
+		if bv, ok := beforeMap[k]; !ok || !reflect.DeepEqual(v, bv) {
+			changedAfter[k] = v
+		}
+	}
+
+	return marshalAuditMap(changedBefore), marshalAuditMap(changedAfter)
+}
+
+func toAuditMap(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func marshalAuditMap(m map[string]interface{}) json.RawMessage {
+	if len(m) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"fmt"
+	"uas/app/apperror"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -12,7 +13,7 @@ func GetUserIDFromContext(c *fiber.Ctx) (string, error) {
 	userIDLocal := c.Locals("user_id")
 	
 	if userIDLocal == nil {
-		return "", fmt.Errorf("unauthorized: User ID tidak ditemukan di context")
+		return "", apperror.Unauthorized(apperror.CodeUnauthorized)
 	}
 
 	switch v := userIDLocal.(type) {
@@ -21,6 +22,6 @@ func GetUserIDFromContext(c *fiber.Ctx) (string, error) {
 	case uuid.UUID:
 		return v.String(), nil
 	default:
-		return "", fmt.Errorf("internal error: Tipe data User ID tidak dikenali")
+		return "", apperror.Internal(fmt.Errorf("tipe data user_id tidak dikenali: %T", v))
 	}
 }
\ No newline at end of file
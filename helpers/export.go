@@ -0,0 +1,307 @@
+package helpers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Format file export
+const (
+	ExportCSV    = "csv"
+	ExportXLSX   = "xlsx"
+	ExportNDJSON = "ndjson"
+)
+
+var exportContentTypes = map[string]string{
+	ExportCSV:    "text/csv; charset=utf-8",
+	ExportXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	ExportNDJSON: "application/x-ndjson",
+}
+
+// Buffer ditulis ke client setiap sejumlah baris agar export besar langsung mengalir
+const exportFlushEvery = 200
+
+// ExportColumn mendefinisikan satu kolom export. Mask diisi untuk kolom data pribadi (PII)
+// dan dipakai jika client meminta ?mask_pii=true.
+type ExportColumn[T any] struct {
+	Name  string
+	Value func(T) interface{}
+	Mask  func(string) string
+}
+
+// ExportOptions adalah hasil ParseExportOptions
+type ExportOptions struct {
+	Format  string
+	Columns []string
+	MaskPII bool
+}
+
+// ParseExportOptions membaca ?format (csv, xlsx, ndjson; default csv), ?columns (dipisah koma, default semua)
+// dan ?mask_pii. available adalah nama kolom yang tersedia sesuai urutan default.
+func ParseExportOptions(c *fiber.Ctx, available []string) (ExportOptions, error) {
+	opts := ExportOptions{Format: strings.ToLower(c.Query("format", ExportCSV)), Columns: available}
+	if _, ok := exportContentTypes[opts.Format]; !ok {
+		return opts, invalidQuery("format")
+	}
+
+	if raw := strings.TrimSpace(c.Query("columns")); raw != "" {
+		opts.Columns = nil
+		for _, col := range strings.Split(raw, ",") {
+			col = strings.TrimSpace(col)
+			if !slices.Contains(available, col) {
+				return opts, invalidQuery("columns").With("column", col)
+			}
+			if !slices.Contains(opts.Columns, col) {
+				opts.Columns = append(opts.Columns, col)
+			}
+		}
+	}
+
+	mask, err := ParseBoolQuery(c, "mask_pii")
+	if err != nil {
+		return opts, err
+	}
+	opts.MaskPII = mask != nil && *mask
+	return opts, nil
+}
+
+// ExportColumnNames mengembalikan nama seluruh kolom sesuai urutan definisi
+func ExportColumnNames[T any](columns []ExportColumn[T]) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// StreamExport mengirim hasil export sebagai file unduhan "<name>-<waktu>.<format>".
+// iterate dipanggil saat body dikirim dan harus meneruskan setiap baris ke fn, sehingga data
+// tidak pernah ditampung seluruhnya di memori. Karena status 200 sudah terkirim, error di
+// tengah export hanya dapat dicatat di log server.
+func StreamExport[T any](c *fiber.Ctx, opts ExportOptions, name string, columns []ExportColumn[T], iterate func(ctx context.Context, fn func(T) error) error) error {
+	selected := make([]ExportColumn[T], 0, len(opts.Columns))
+	for _, colName := range opts.Columns {
+		for _, col := range columns {
+			if col.Name == colName {
+				selected = append(selected, col)
+			}
+		}
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), opts.Format)
+	c.Set(fiber.HeaderContentType, exportContentTypes[opts.Format])
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// fiber.Ctx tidak boleh dipakai lagi setelah handler selesai, context diambil lebih dulu
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		out, err := newExportWriter(opts.Format, w, ExportColumnNames(selected))
+		if err == nil {
+			count := 0
+			err = iterate(ctx, func(item T) error {
+				values := make([]interface{}, len(selected))
+				for i, col := range selected {
+					values[i] = col.Value(item)
+					if opts.MaskPII && col.Mask != nil {
+						values[i] = col.Mask(fmt.Sprint(values[i]))
+					}
+				}
+				if err := out.WriteRow(values); err != nil {
+					return err
+				}
+				if count++; count%exportFlushEvery == 0 {
+					return out.Flush()
+				}
+				return nil
+			})
+		}
+		// Close tetap dipanggil saat gagal agar file sementara XLSX dibersihkan
+		if out != nil {
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			utils.Logger(ctx).Error("export gagal di tengah jalan", "export", name, "format", opts.Format, "error", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// exportWriter menulis baris export ke format tertentu
+type exportWriter interface {
+	WriteRow(values []interface{}) error
+	Flush() error
+	Close() error
+}
+
+func newExportWriter(format string, w *bufio.Writer, header []string) (exportWriter, error) {
+	switch format {
+	case ExportXLSX:
+		xw, err := newXLSXExportWriter(w, header)
+		if xw == nil {
+			return nil, err
+		}
+		return xw, err
+	case ExportNDJSON:
+		return &ndjsonExportWriter{w: w, header: header}, nil
+	default:
+		cw := csv.NewWriter(w)
+		return &csvExportWriter{w: cw, buf: w}, cw.Write(header)
+	}
+}
+
+type csvExportWriter struct {
+	w   *csv.Writer
+	buf *bufio.Writer
+}
+
+func (e *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = exportString(v)
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExportWriter) Flush() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+func (e *csvExportWriter) Close() error { return e.Flush() }
+
+// ndjsonExportWriter menulis satu objek JSON per baris dengan urutan key sesuai kolom
+type ndjsonExportWriter struct {
+	w      *bufio.Writer
+	header []string
+}
+
+func (e *ndjsonExportWriter) WriteRow(values []interface{}) error {
+	e.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.header[i])
+		val, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		e.w.Write(key)
+		e.w.WriteByte(':')
+		e.w.Write(val)
+	}
+	e.w.WriteString("}\n")
+	return nil
+}
+
+func (e *ndjsonExportWriter) Flush() error { return e.w.Flush() }
+func (e *ndjsonExportWriter) Close() error { return e.w.Flush() }
+
+// xlsxExportWriter memakai StreamWriter excelize yang menampung baris di file sementara,
+// bukan di memori, lalu menyalin workbook ke client saat Close
+type xlsxExportWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	row    int
+}
+
+func newXLSXExportWriter(w io.Writer, header []string) (*xlsxExportWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	e := &xlsxExportWriter{file: f, stream: sw, out: w}
+	values := make([]interface{}, len(header))
+	for i, h := range header {
+		values[i] = h
+	}
+	return e, e.WriteRow(values)
+}
+
+func (e *xlsxExportWriter) WriteRow(values []interface{}) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			values[i] = exportString(t)
+		}
+	}
+	return e.stream.SetRow(cell, values)
+}
+
+func (e *xlsxExportWriter) Flush() error { return nil }
+
+func (e *xlsxExportWriter) Close() error {
+	defer e.file.Close()
+	if err := e.stream.Flush(); err != nil {
+		return err
+	}
+	return e.file.Write(e.out)
+}
+
+func exportString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// MaskEmail menyamarkan bagian lokal email: "andi@unair.ac.id" menjadi "a***@unair.ac.id"
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return MaskIdentifier(email)
+	}
+	return local[:1] + "***@" + domain
+}
+
+// MaskIdentifier hanya menyisakan 4 karakter terakhir, misalnya NIM/NIP
+func MaskIdentifier(id string) string {
+	if len(id) <= 4 {
+		return strings.Repeat("*", len(id))
+	}
+	return strings.Repeat("*", len(id)-4) + id[len(id)-4:]
+}
+
+// MaskName hanya menyisakan huruf pertama setiap kata: "Andi Pratama" menjadi "A*** P***"
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		r := []rune(w)
+		words[i] = string(r[0]) + "***"
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"uas/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Lang mengambil bahasa respons yang sudah dinegosiasikan middleware Language
+func Lang(c *fiber.Ctx) i18n.Lang {
+	if lang, ok := c.Locals("lang").(i18n.Lang); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+// SetLang menetapkan bahasa respons untuk sisa request
+func SetLang(c *fiber.Ctx, lang i18n.Lang) {
+	c.Locals("lang", lang)
+	c.Set(fiber.HeaderContentLanguage, string(lang))
+}
+
+// Message menerjemahkan kunci pesan sukses ke bahasa request
+func Message(c *fiber.Ctx, key string) string {
+	return i18n.T(Lang(c), key, nil)
+}
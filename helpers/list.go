@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"uas/app/apperror"
+	"uas/app/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ParseListParams membaca query ?page, ?limit, ?cursor, ?sort, ?order dan ?q untuk endpoint daftar.
+// sortFields adalah kolom yang boleh dipakai untuk sorting; kolom pertama menjadi default.
+// Sort dapat ditulis "-full_name" sebagai singkatan order=desc.
+func ParseListParams(c *fiber.Ctx, sortFields []string) (models.ListParams, error) {
+	params := models.ListParams{
+		Page:   1,
+		Limit:  models.DefaultListLimit,
+		Sort:   sortFields[0],
+		Order:  models.OrderAsc,
+		Search: strings.TrimSpace(c.Query("q")),
+	}
+
+	var err error
+	if params.Page, params.Limit, err = ParsePageParams(c); err != nil {
+		return params, err
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		if strings.HasPrefix(sort, "-") {
+			sort, params.Order = sort[1:], models.OrderDesc
+		}
+		if !slices.Contains(sortFields, sort) {
+			return params, invalidQuery("sort").With("values", strings.Join(sortFields, ", "))
+		}
+		params.Sort = sort
+	}
+
+	switch order := strings.ToLower(c.Query("order")); order {
+	case "":
+	case models.OrderAsc, models.OrderDesc:
+		params.Order = order
+	default:
+		return params, invalidQuery("order")
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := models.DecodeCursor(raw)
+		if err != nil || cursor.Sort != params.Sort || cursor.Order != params.Order {
+			return params, invalidQuery("cursor")
+		}
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+// ParsePageParams membaca ?page dan ?limit untuk endpoint berhalaman tanpa sorting; limit di atas
+// MaxListLimit dipotong ke MaxListLimit
+func ParsePageParams(c *fiber.Ctx) (int, int, error) {
+	page, err := positiveQuery(c, "page", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err := positiveQuery(c, "limit", models.DefaultListLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	return page, min(limit, models.MaxListLimit), nil
+}
+
+// ParseBoolQuery membaca query boolean opsional seperti ?is_active=true; nil jika tidak dikirim
+func ParseBoolQuery(c *fiber.Ctx, name string) (*bool, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, invalidQuery(name)
+	}
+	return &v, nil
+}
+
+// ParseUUIDQuery membaca query UUID opsional, contoh ?advisor_id=
+func ParseUUIDQuery(c *fiber.Ctx, name string) (string, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return "", nil
+	}
+	if _, err := uuid.Parse(raw); err != nil {
+		return "", invalidQuery(name)
+	}
+	return raw, nil
+}
+
+// ParseEnumQuery membaca query opsional yang nilainya harus salah satu dari allowed, contoh ?status=
+func ParseEnumQuery(c *fiber.Ctx, name string, allowed []string) (string, error) {
+	raw := c.Query(name)
+	if raw == "" || slices.Contains(allowed, raw) {
+		return raw, nil
+	}
+	return "", invalidQuery(name).With("values", strings.Join(allowed, ", "))
+}
+
+func positiveQuery(c *fiber.Ctx, name string, def int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 {
+		return 0, invalidQuery(name)
+	}
+	return v, nil
+}
+
+func invalidQuery(param string) *apperror.Error {
+	return apperror.BadRequest(apperror.CodeInvalidQuery).With("param", param)
+}
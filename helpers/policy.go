@@ -0,0 +1,32 @@
+package helpers
+
+import "uas/app/models"
+
+// IsValidScope mengecek apakah scope termasuk scope permission yang dikenal
+func IsValidScope(scope string) bool {
+	for _, s := range models.PermissionScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateScope menentukan apakah subject boleh mengakses resource sesuai scope permission-nya
+func EvaluateScope(scope string, subject models.PolicySubject, resource models.PolicyResource) bool {
+	switch scope {
+	case models.ScopeAll:
+		return true
+	case models.ScopeOwn:
+		if subject.StudentID != "" && subject.StudentID == resource.StudentID {
+			return true
+		}
+		return subject.LecturerID != "" && subject.LecturerID == resource.LecturerID
+	case models.ScopeAdvisees:
+		return subject.LecturerID != "" && subject.LecturerID == resource.AdvisorID
+	case models.ScopeDepartment:
+		return subject.Department != "" && subject.Department == resource.Department
+	default:
+		return false
+	}
+}
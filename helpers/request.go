@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"uas/app/apperror"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ParseUUIDParam mengambil parameter path (misal :id) yang wajib berformat UUID
+func ParseUUIDParam(c *fiber.Ctx, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Params(name))
+	if err != nil {
+		return uuid.Nil, apperror.BadRequest(apperror.CodeInvalidID).With("param", name)
+	}
+	return id, nil
+}
+
+// ParseBody mem-parsing body request ke out
+func ParseBody(c *fiber.Ctx, out interface{}) error {
+	if err := c.BodyParser(out); err != nil {
+		return apperror.Wrap(err, apperror.BadRequest(apperror.CodeInvalidBody))
+	}
+	return nil
+}
+
+// ParseAndValidate mem-parsing body request ke out lalu memvalidasinya berdasarkan tag `validate`.
+// Dipakai oleh seluruh handler yang menerima body.
+func ParseAndValidate(c *fiber.Ctx, out interface{}) error {
+	if err := ParseBody(c, out); err != nil {
+		return err
+	}
+	return Validate(out)
+}
@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/app/repository"
+)
+
+// ValidateAdminPermissionRemains memastikan setiap permission "users:*" dan "roles:*" pada daftar perms
+// masih dimiliki minimal satu admin aktif lain setelah role/user yang dikecualikan kehilangan akses tersebut.
+// Admin efektif adalah pemegang scope all, karena route users:* dan roles:* tidak memakai resolver resource
+// sehingga scope lain ditolak RequirePermission.
+func ValidateAdminPermissionRemains(ctx context.Context, repo repository.RoleRepository, perms []models.Permission, excludeRoleID string, excludeUserID string) error {
+	for _, p := range perms {
+		if !strings.HasPrefix(p.Name, "users:") && !strings.HasPrefix(p.Name, "roles:") {
+			continue
+		}
+
+		count, err := repo.CountUsersWithPermission(ctx, p.Name, excludeRoleID, excludeUserID)
+		if err != nil {
+			return fmt.Errorf("gagal memvalidasi permission admin: %w", err)
+		}
+
+		if count == 0 {
+			return apperror.Conflict(apperror.CodeLastAdminPermission).With("permission", p.Name)
+		}
+	}
+
+	return nil
+}
+
+// PrivilegedPermission mengembalikan permission administratif pertama (users:*, roles:* atau audit:read)
+// pada daftar perms, kosong jika tidak ada
+func PrivilegedPermission(perms []models.Permission) string {
+	for _, p := range perms {
+		if strings.HasPrefix(p.Name, "users:") || strings.HasPrefix(p.Name, "roles:") || p.Name == "audit:read" {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// IsSystemRole mengecek apakah nama role termasuk role bawaan sistem
+func IsSystemRole(name string) bool {
+	for _, r := range models.SystemRoles {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
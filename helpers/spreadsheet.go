@@ -0,0 +1,164 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"uas/app/apperror"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format file tabel yang dapat dibaca ReadSpreadsheet
+const (
+	SpreadsheetCSV  = ".csv"
+	SpreadsheetXLSX = ".xlsx"
+)
+
+// ReadSpreadsheet membaca file CSV/XLSX hasil upload menjadi header (huruf kecil) dan baris data.
+// Baris kosong dilewati dan nomor baris asli dikembalikan di lines agar laporan sesuai dengan file.
+// Jumlah baris data dibatasi maxRows.
+func ReadSpreadsheet(file *multipart.FileHeader, maxRows int) (header []string, rows [][]string, lines []int, err error) {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != SpreadsheetCSV && ext != SpreadsheetXLSX {
+		return nil, nil, nil, apperror.BadRequest(apperror.CodeImportFormatUnsupported).With("format", ext)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, nil, nil, apperror.Internal(fmt.Errorf("gagal membuka file upload: %w", err))
+	}
+	defer f.Close()
+
+	var records [][]string
+	var recordLines []int
+	if ext == SpreadsheetCSV {
+		records, recordLines, err = readCSV(f, maxRows)
+	} else {
+		records, recordLines, err = readXLSX(f, maxRows)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for i, record := range records {
+		if isBlankRecord(record) {
+			continue
+		}
+		if header == nil {
+			header = make([]string, len(record))
+			for j, col := range record {
+				header[j] = strings.ToLower(strings.TrimSpace(col))
+			}
+			continue
+		}
+		rows = append(rows, record)
+		lines = append(lines, recordLines[i])
+	}
+
+	if header == nil {
+		return nil, nil, nil, apperror.BadRequest(apperror.CodeImportFileInvalid).With("reason", "empty")
+	}
+	if len(rows) > maxRows {
+		return nil, nil, nil, apperror.BadRequest(apperror.CodeImportTooManyRows).With("max", maxRows)
+	}
+	return header, rows, lines, nil
+}
+
+// readCSV mendukung pemisah koma maupun titik koma (format CSV Excel berlocale Indonesia)
+func readCSV(r io.Reader, maxRows int) ([][]string, []int, error) {
+	br := bufio.NewReader(r)
+	firstLine, _ := br.Peek(4096)
+	if i := bytes.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	var records [][]string
+	var lines []int
+	filled := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, apperror.Wrap(err, apperror.BadRequest(apperror.CodeImportFileInvalid).With("reason", err.Error()))
+		}
+		// BOM dari Excel ikut terbaca di kolom pertama
+		if len(records) == 0 && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		// encoding/csv melewati baris kosong, nomor baris diambil dari posisi field pertama
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+		// Header + maxRows + 1 baris terisi cukup untuk mendeteksi file yang melebihi batas
+		if !isBlankRecord(record) {
+			filled++
+		}
+		if filled > maxRows+1 {
+			break
+		}
+	}
+	return records, lines, nil
+}
+
+// readXLSX membaca sheet pertama secara streaming
+func readXLSX(r io.Reader, maxRows int) ([][]string, []int, error) {
+	wb, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, apperror.BadRequest(apperror.CodeImportFileInvalid).With("reason", "xlsx"))
+	}
+	defer wb.Close()
+
+	sheets := wb.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil, apperror.BadRequest(apperror.CodeImportFileInvalid).With("reason", "empty")
+	}
+
+	it, err := wb.Rows(sheets[0])
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, apperror.BadRequest(apperror.CodeImportFileInvalid).With("reason", "xlsx"))
+	}
+	defer it.Close()
+
+	var records [][]string
+	var lines []int
+	filled := 0
+	for it.Next() {
+		record, err := it.Columns()
+		if err != nil {
+			return nil, nil, apperror.Wrap(err, apperror.BadRequest(apperror.CodeImportFileInvalid).With("reason", "xlsx"))
+		}
+		records = append(records, record)
+		lines = append(lines, len(records))
+		if !isBlankRecord(record) {
+			filled++
+		}
+		if filled > maxRows+1 {
+			break
+		}
+	}
+	return records, lines, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, col := range record {
+		if strings.TrimSpace(col) != "" {
+			return false
+		}
+	}
+	return true
+}
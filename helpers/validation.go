@@ -0,0 +1,152 @@
+package helpers
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/i18n"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+)
+
+// Format identitas kampus
+var (
+	nimPattern      = regexp.MustCompile(`^[0-9]{8,15}$`)
+	nipPattern      = regexp.MustCompile(`^[0-9]{18}$`)
+	usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.]{3,50}$`)
+)
+
+// Panjang minimal password user
+const minPasswordLength = 8
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Nama field pada error mengikuti tag json agar sama dengan yang dikirim client
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	v.RegisterValidation("notblank", validators.NotBlank)
+	v.RegisterValidation("nim", matchPattern(nimPattern))
+	v.RegisterValidation("nip", matchPattern(nipPattern))
+	v.RegisterValidation("username", matchPattern(usernamePattern))
+	v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return IsStrongPassword(fl.Field().String())
+	})
+	v.RegisterValidation("achievement_type", oneOf(models.AchievementTypes))
+	v.RegisterValidation("scope", oneOf(models.PermissionScopes))
+
+	return v
+}
+
+// Validate menjalankan aturan pada tag `validate` dan mengembalikan error validasi per field
+func Validate(v interface{}) error {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return apperror.Internal(err)
+	}
+
+	fields := make([]apperror.FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		code, params := fieldCode(fe)
+		fields = append(fields, apperror.FieldError{
+			Field:   fieldPath(fe),
+			Code:    code,
+			Message: i18n.T(i18n.Default, FieldMessageKey(code), params),
+			Params:  params,
+		})
+	}
+	return apperror.Validation(fields...)
+}
+
+// IsStrongPassword: minimal 8 karakter serta mengandung huruf dan angka
+func IsStrongPassword(password string) bool {
+	if len(password) < minPasswordLength {
+		return false
+	}
+	hasLetter := strings.IndexFunc(password, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	}) >= 0
+	hasDigit := strings.IndexFunc(password, func(r rune) bool {
+		return r >= '0' && r <= '9'
+	}) >= 0
+	return hasLetter && hasDigit
+}
+
+// fieldPath membuang nama struct root, contoh "CreateUserRequest.student.student_id" -> "student.student_id"
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// fieldCode menentukan kode (kunci katalog "validation.<kode>") beserta params pesannya.
+// min/max pada string dibedakan agar pesan menyebut satuan karakter.
+func fieldCode(fe validator.FieldError) (string, map[string]interface{}) {
+	switch fe.Tag() {
+	case "required", "notblank", "email", "numeric", "nim", "nip", "username":
+		return fe.Tag(), nil
+	case "uuid", "uuid4":
+		return "uuid", nil
+	case "min", "max":
+		if fe.Kind() == reflect.String {
+			return fe.Tag() + "_length", map[string]interface{}{"param": fe.Param()}
+		}
+		return fe.Tag(), map[string]interface{}{"param": fe.Param()}
+	case "len":
+		return "len", map[string]interface{}{"param": fe.Param()}
+	case "oneof":
+		return "oneof", map[string]interface{}{"values": strings.ReplaceAll(fe.Param(), " ", ", ")}
+	case "password":
+		return "password", map[string]interface{}{"param": minPasswordLength}
+	case "achievement_type":
+		return fe.Tag(), map[string]interface{}{"values": strings.Join(models.AchievementTypes, ", ")}
+	case "scope":
+		return fe.Tag(), map[string]interface{}{"values": strings.Join(models.PermissionScopes, ", ")}
+	default:
+		return fe.Tag(), nil
+	}
+}
+
+// FieldMessageKey memetakan kode field ke kunci katalog; kode tanpa pesan khusus memakai validation.invalid
+func FieldMessageKey(code string) string {
+	if key := "validation." + code; i18n.Has(key) {
+		return key
+	}
+	return "validation.invalid"
+}
+
+func matchPattern(re *regexp.Regexp) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return re.MatchString(fl.Field().String())
+	}
+}
+
+func oneOf(values []string) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		for _, v := range values {
+			if fl.Field().String() == v {
+				return true
+			}
+		}
+		return false
+	}
+}
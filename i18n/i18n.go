@@ -0,0 +1,131 @@
+// Package i18n berisi katalog pesan respons (Indonesia & Inggris) beserta negosiasi bahasa.
+// Pesan error dikunci dengan kode error dari app/apperror, pesan sukses dengan kunci di keys.go.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Lang string
+
+const (
+	ID Lang = "id"
+	EN Lang = "en"
+
+	// Default dipakai jika client tidak meminta bahasa yang didukung
+	Default = ID
+)
+
+// Supported berisi bahasa yang memiliki katalog
+var Supported = []Lang{ID, EN}
+
+//go:embed locales/*.yaml
+var localeFS embed.FS
+
+var catalogs = mustLoad()
+
+func mustLoad() map[Lang]map[string]string {
+	out := make(map[Lang]map[string]string, len(Supported))
+	for _, lang := range Supported {
+		raw, err := localeFS.ReadFile("locales/" + string(lang) + ".yaml")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: katalog %s tidak ditemukan: %v", lang, err))
+		}
+
+		messages := map[string]string{}
+		if err := yaml.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: katalog %s tidak valid: %v", lang, err))
+		}
+		out[lang] = messages
+	}
+	return out
+}
+
+// Parse mengenali kode bahasa, termasuk varian regional seperti "en-US" atau "id_ID"
+func Parse(s string) (Lang, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "-_"); i >= 0 {
+		s = s[:i]
+	}
+	for _, lang := range Supported {
+		if s == string(lang) {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate memilih bahasa dari header Accept-Language berdasarkan bobot q, atau Default
+func Negotiate(acceptLanguage string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Has mengecek apakah kunci tersedia di katalog Default
+func Has(key string) bool {
+	_, ok := catalogs[Default][key]
+	return ok
+}
+
+// Keys mengembalikan seluruh kunci katalog suatu bahasa
+func Keys(lang Lang) []string {
+	keys := make([]string, 0, len(catalogs[lang]))
+	for k := range catalogs[lang] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// T menerjemahkan kunci ke bahasa lang dan mengisi placeholder {nama} dari params.
+// Jika kunci tidak ada, dipakai katalog Default lalu kunci itu sendiri.
+func T(lang Lang, key string, params map[string]interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+
+	if len(params) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
@@ -0,0 +1,60 @@
+package i18n
+
+// Kunci pesan sukses. Pesan error memakai kode error dari app/apperror sebagai kunci,
+// pesan validasi per field memakai "validation.<tag>".
+const (
+	MsgUsersFetched             = "users.fetched"
+	MsgUsersEmpty               = "users.empty"
+	MsgUserFound                = "user.found"
+	MsgUserCreated              = "user.created"
+	MsgUserUpdated              = "user.updated"
+	MsgUserDeleted              = "user.deleted"
+	MsgUserAnonymized           = "user.anonymized"
+	MsgUserRestored             = "user.restored"
+	MsgUserRoleUpdated          = "user.role_updated"
+	MsgUserImpersonationCreated = "user.impersonation_created"
+	MsgUsersImportValidated     = "users.import_validated"
+	MsgUsersImported            = "users.imported"
+	MsgAuthLogin                = "auth.login"
+	MsgAuthTokenRefreshed       = "auth.token_refreshed"
+	MsgAuthProfileFetched       = "auth.profile_fetched"
+	MsgAuthPreferencesUpdated   = "auth.preferences_updated"
+	MsgAuthLogout               = "auth.logout"
+	MsgAuthSessionsFetched      = "auth.sessions_fetched"
+	MsgAuthSessionRevoked       = "auth.session_revoked"
+	MsgAuthForceLogout          = "auth.force_logout"
+	MsgRolesFetched             = "roles.fetched"
+	MsgRolesEmpty               = "roles.empty"
+	MsgRoleFound                = "role.found"
+	MsgRoleCreated              = "role.created"
+	MsgRoleUpdated              = "role.updated"
+	MsgRoleDeleted              = "role.deleted"
+	MsgRolePermissionGranted    = "role.permission_granted"
+	MsgRolePermissionRevoked    = "role.permission_revoked"
+	MsgPermissionsFetched       = "permissions.fetched"
+	MsgPermissionsEmpty         = "permissions.empty"
+	MsgStudentsFetched          = "students.fetched"
+	MsgStudentsEmpty            = "students.empty"
+	MsgStudentFound             = "student.found"
+	MsgStudentAdvisorUpdated    = "student.advisor_updated"
+	MsgStudentUpdated           = "student.updated"
+	MsgStudentStatusUpdated     = "student.status_updated"
+	MsgStudentStatusHistory     = "student.status_history_fetched"
+	MsgLecturersFetched         = "lecturers.fetched"
+	MsgLecturersEmpty           = "lecturers.empty"
+	MsgLecturerFound            = "lecturer.found"
+	MsgLecturerAdviseesFetched  = "lecturer.advisees_fetched"
+	MsgLecturerAdviseesEmpty    = "lecturer.advisees_empty"
+	MsgLecturerCreated          = "lecturer.created"
+	MsgLecturerUpdated          = "lecturer.updated"
+	MsgAdviseesReassigned       = "lecturer.advisees_reassigned"
+	MsgAchievementCreated       = "achievement.created"
+	MsgAchievementUpdated       = "achievement.updated"
+	MsgAchievementDeleted       = "achievement.deleted"
+	MsgAchievementSubmitted     = "achievement.submitted"
+	MsgAchievementVerified      = "achievement.verified"
+	MsgAchievementRejected      = "achievement.rejected"
+	MsgAuditFetched             = "audit.fetched"
+	MsgAuditChainValid          = "audit.chain_valid"
+	MsgAuditChainBroken         = "audit.chain_broken"
+)
@@ -1,35 +1,151 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"uas/app/container"
+	"uas/app/services"
 	"uas/config"
 	"uas/database"
+	"uas/metrics"
+	"uas/middleware"
 	"uas/routes"
+	"uas/tracing"
+	"uas/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 func main() {
 
-	// Menghubungkan ENV
-	config.Config();
+	// Memuat & memvalidasi konfigurasi (ENV > file > default)
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// Database postgre	SQL
-	postgreSQL := database.ConnectDB()
-	mongoDB := database.ConnectMongoDB()
+	// Logger terstruktur; package log bawaan juga diarahkan ke handler ini
+	logger := utils.NewLogger(cfg.Log, os.Stdout)
+	slog.SetDefault(logger)
+
+	// Context dibatalkan saat menerima SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Subcommand: `uas migrate ...`, tanpa argumen menjalankan server
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, cfg, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// JWT
+	utils.ConfigureJWT(cfg.JWT)
+
+	// Tracing OpenTelemetry (TRACING_EXPORTER=none|stdout|otlp)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, config.Version)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Database PostgreSQL & MongoDB, dicoba ulang jika belum tersedia
+	postgreSQL, err := connectPostgres(ctx, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var mongoDB *mongo.Database
+	err = database.Retry(ctx, "MongoDB", cfg.App.StartupRetries, cfg.App.StartupRetryDelay, func(ctx context.Context) (err error) {
+		monitor := database.ChainCommandMonitors(otelmongo.NewMonitor(), metrics.MongoMonitor())
+		mongoDB, err = database.ConnectMongoDB(ctx, cfg.Mongo, options.Client().SetMonitor(monitor))
+		return err
+	})
+	if err != nil {
+		postgreSQL.Close()
+		log.Fatal(err)
+	}
+
+	// Statistik pool PostgreSQL untuk /metrics
+	if err := metrics.RegisterDBStats(postgreSQL); err != nil {
+		slog.Warn("gagal mendaftarkan metrik pool PostgreSQL", "error", err)
+	}
+
+	// Auto-migrate (opsional) sebelum server menerima request
+	if cfg.App.AutoMigrate {
+		if err := database.MigrateUp(ctx, postgreSQL, cfg.Mongo); err != nil {
+			postgreSQL.Close()
+			mongoDB.Client().Disconnect(context.Background())
+			log.Fatal(err)
+		}
+	}
+
+	// Container aplikasi: satu pool untuk seluruh repository & service
+	application := container.New(cfg, postgreSQL, mongoDB)
+
+	// Sesi yang dicabut sebelum restart tetap ditolak
+	services.LoadRevokedSessions(application.Repositories.Session)
+
+	// Background worker berhenti bersama ctx
+	application.Go(ctx, "session-denylist", container.Every(time.Minute, utils.PruneDeniedSessions))
 
 	// Inisialisasi fiber
 	app := fiber.New(fiber.Config{
-		ErrorHandler: func (c *fiber.Ctx, err error) error {
-			return c.Status(500).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
+		AppName:      cfg.App.Name,
+		ErrorHandler: middleware.ErrorHandler,
 	})
 
 	// routes
-	routes.SetupRoutes(app, postgreSQL, mongoDB)
+	routes.SetupRoutes(app, application)
 
 	// Server
-	log.Fatal(app.Listen(":3000"))
-}
\ No newline at end of file
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- app.Listen(cfg.App.Addr())
+	}()
+
+	select {
+	case err := <-serverErr:
+		slog.Error("server berhenti", "error", err)
+		stop()
+	case <-ctx.Done():
+		slog.Info("sinyal shutdown diterima, menunggu request yang sedang berjalan", "timeout", cfg.App.ShutdownTimeout.String())
+	}
+
+	// Shutdown berurutan: HTTP server dulu, lalu worker dan koneksi database
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
+	defer cancel()
+
+	if err := app.ShutdownWithContext(shutdownCtx); err != nil {
+		slog.Error("gagal menghentikan server", "error", err)
+	}
+
+	if err := application.Shutdown(shutdownCtx); err != nil {
+		slog.Error("gagal menutup dependensi", "error", err)
+	}
+
+	// Span yang masih di buffer dikirim paling akhir
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("gagal mengirim sisa trace", "error", err)
+	}
+
+	slog.Info("server berhenti")
+}
+
+func connectPostgres(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
+	var db *sql.DB
+	err := database.Retry(ctx, "PostgreSQL", cfg.App.StartupRetries, cfg.App.StartupRetryDelay, func(ctx context.Context) (err error) {
+		db, err = database.ConnectDB(ctx, cfg.Postgres)
+		return err
+	})
+	return db, err
+}
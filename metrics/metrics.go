@@ -0,0 +1,110 @@
+// Package metrics berisi metrik Prometheus aplikasi yang diekspos di GET /metrics
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "uas"
+
+// Registry khusus aplikasi agar metrik tidak bercampur dengan default registry library lain
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Jumlah request HTTP per route, method dan status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latensi request HTTP per route, method dan status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	mongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_command_duration_seconds",
+		Help:      "Latensi perintah MongoDB per nama perintah dan hasil.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"command", "result"})
+
+	achievements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "achievements_total",
+		Help:      "Jumlah transisi prestasi: created, submitted, verified, rejected.",
+	}, []string{"event"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Jumlah percobaan login per hasil (success/failure) dan alasan.",
+	}, []string{"result", "reason"})
+)
+
+// Event prestasi untuk AchievementEvent
+const (
+	AchievementCreated   = "created"
+	AchievementSubmitted = "submitted"
+	AchievementVerified  = "verified"
+	AchievementRejected  = "rejected"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		mongoDuration,
+		achievements,
+		logins,
+	)
+
+	// Inisialisasi label agar seri bernilai 0 sudah muncul sebelum event pertama
+	for _, event := range []string{AchievementCreated, AchievementSubmitted, AchievementVerified, AchievementRejected} {
+		achievements.WithLabelValues(event)
+	}
+	logins.WithLabelValues("success", "")
+}
+
+// RegisterDBStats mengekspos sql.DBStats pool PostgreSQL sebagai gauge
+func RegisterDBStats(db *sql.DB) error {
+	err := Registry.Register(collectors.NewDBStatsCollector(db, "postgres"))
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		return nil
+	}
+	return err
+}
+
+func ObserveHTTP(method string, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func ObserveMongo(command string, result string, duration time.Duration) {
+	mongoDuration.WithLabelValues(command, result).Observe(duration.Seconds())
+}
+
+func AchievementEvent(event string) {
+	achievements.WithLabelValues(event).Inc()
+}
+
+func LoginSucceeded() {
+	logins.WithLabelValues("success", "").Inc()
+}
+
+// LoginFailed mencatat login gagal beserta alasannya (unknown_user, wrong_password, inactive)
+func LoginFailed(reason string) {
+	logins.WithLabelValues("failure", reason).Inc()
+}
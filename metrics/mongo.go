@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// MongoMonitor mencatat durasi setiap perintah MongoDB ke mongo_command_duration_seconds
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			ObserveMongo(e.CommandName, "success", e.Duration)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			ObserveMongo(e.CommandName, "failure", e.Duration)
+		},
+	}
+}
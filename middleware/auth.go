@@ -2,11 +2,13 @@ package middleware
 
 import (
 	"strings"
-	"uas/app/repository"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/helpers"
+	"uas/i18n"
 	"uas/utils"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 func AuthRequired() fiber.Handler {
@@ -14,66 +16,50 @@ func AuthRequired() fiber.Handler {
 		// Ambil token dari header Authorization
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return c.Status(401).JSON(fiber.Map{
-				"error": "Token akses diperlukan",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenRequired)
 		}
 
 		// Extract token dari "Bearer TOKEN"
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			return c.Status(401).JSON(fiber.Map{
-				"error": "Format token tidak valid",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenMalformed)
 		}
 
 		// Validasi token
 		claims, err := utils.ValidateToken(tokenParts[1])
 		if err != nil {
-			return c.Status(401).JSON(fiber.Map{
-				"error": "Token tidak valid atau expired",
-			})
+			return apperror.Unauthorized(apperror.CodeTokenInvalid)
+		}
+
+		// Tolak token dari sesi yang sudah dicabut (logout / force logout)
+		if claims.SessionID != "" && utils.IsSessionDenied(claims.SessionID) {
+			return apperror.Unauthorized(apperror.CodeSessionRevoked)
 		}
 
 		// Simpan informasi user di context
 		c.Locals("user_id", claims.UserID)
 		c.Locals("username", claims.Username)
 		c.Locals("role_name", claims.RoleName)
+		c.Locals("session_id", claims.SessionID)
+		if claims.Actor != nil {
+			c.Locals("actor", claims.Actor)
+		}
+
+		// Preferensi bahasa yang disimpan user lebih diutamakan daripada Accept-Language
+		if lang, ok := i18n.Parse(claims.Language); ok {
+			helpers.SetLang(c, lang)
+		}
 
 		return c.Next()
 	}
 }
 
-// Menerima parameter string 'perm' (misal: "achievement:create")
-func RequirePermission(perm string) fiber.Handler {
-    return func(c *fiber.Ctx) error {
-        // 1. Ambil User ID dari Locals (yang diset oleh AuthRequired)
-        userIDLocal := c.Locals("user_id")
-        
-        if userIDLocal == nil {
-            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: User ID not found"})
-        }
-
-        // Pastikan tipe datanya UUID
-        userID, ok := userIDLocal.(uuid.UUID)
-        if !ok {
-             return c.Status(500).JSON(fiber.Map{"error": "Internal Server Error: Invalid User ID format"})
-        }
-
-        // 2. Cek ke Database via Repository
-        allowed, err := repository.CheckPermission(userID, perm)
-        if err != nil {
-            return c.Status(500).JSON(fiber.Map{"error": "Gagal memverifikasi izin"})
-        }
-
-        // 3. Logika Allow/Deny
-        if !allowed {
-            return c.Status(403).JSON(fiber.Map{
-                "error": "Forbidden: Anda tidak memiliki izin '" + perm + "'",
-            })
-        }
-
-        // 4. Lanjut ke Controller
-        return c.Next()
-    }
-}
\ No newline at end of file
+// DenyImpersonation menolak aksi sensitif (password, role, MFA) selama sesi impersonasi
+func DenyImpersonation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if actor, ok := c.Locals("actor").(*models.ActorClaim); ok && actor != nil {
+			return apperror.Forbidden(apperror.CodeImpersonationForbidden)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"errors"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/helpers"
+	"uas/i18n"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+)
+
+// Status HTTP untuk tiap jenis error domain
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindBadRequest:   fiber.StatusBadRequest,
+	apperror.KindValidation:   fiber.StatusBadRequest,
+	apperror.KindUnauthorized: fiber.StatusUnauthorized,
+	apperror.KindForbidden:    fiber.StatusForbidden,
+	apperror.KindNotFound:     fiber.StatusNotFound,
+	apperror.KindConflict:     fiber.StatusConflict,
+	apperror.KindInternal:     fiber.StatusInternalServerError,
+}
+
+// Kode untuk error bawaan fiber (route tidak ada, body terlalu besar, dst)
+var fiberStatusCode = map[int]string{
+	fiber.StatusBadRequest:            apperror.CodeBadRequest,
+	fiber.StatusUnauthorized:          apperror.CodeUnauthorized,
+	fiber.StatusForbidden:             apperror.CodeForbidden,
+	fiber.StatusNotFound:              apperror.CodeRouteNotFound,
+	fiber.StatusMethodNotAllowed:      apperror.CodeMethodNotAllowed,
+	fiber.StatusRequestEntityTooLarge: "PAYLOAD_TOO_LARGE",
+	fiber.StatusTooManyRequests:       "TOO_MANY_REQUESTS",
+	fiber.StatusServiceUnavailable:    "SERVICE_UNAVAILABLE",
+}
+
+// ErrorHandler adalah satu-satunya tempat error dipetakan ke respons HTTP.
+// Handler cukup mengembalikan error; detail error internal hanya dicatat di log, tidak pernah dikirim ke client.
+// Pesan disusun dari katalog i18n sesuai bahasa request, berdasarkan kode dan params error.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status, appErr, message := normalize(err)
+
+	if status >= fiber.StatusInternalServerError {
+		utils.Logger(c.UserContext()).Error("request gagal",
+			"path", c.Path(), "code", appErr.Code, "error", err)
+	}
+
+	lang := helpers.Lang(c)
+	if message == "" {
+		message = i18n.T(lang, appErr.Code, appErr.Params)
+	}
+
+	fields := make([]apperror.FieldError, len(appErr.Fields))
+	for i, f := range appErr.Fields {
+		f.Message = i18n.T(lang, helpers.FieldMessageKey(f.Code), f.Params)
+		fields[i] = f
+	}
+
+	requestID, _ := c.Locals("request_id").(string)
+
+	return c.Status(status).JSON(models.ErrorResponse{
+		Success: false,
+		Message: message,
+		Error: models.ErrorDetail{
+			Code:    appErr.Code,
+			Message: message,
+			Fields:  fields,
+			Params:  appErr.Params,
+		},
+		RequestID: requestID,
+	})
+}
+
+// responseStatus mengembalikan status HTTP untuk request yang sudah selesai. Middleware yang berjalan
+// sebelum ErrorHandler memakai pemetaan yang sama agar status yang dicatat sesuai response.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	status, _, _ := normalize(err)
+	return status
+}
+
+// normalize mengubah error apa pun menjadi *apperror.Error beserta status HTTP-nya.
+// message hanya terisi untuk fiber.Error dengan pesan kustom yang tidak ada di katalog.
+func normalize(err error) (int, *apperror.Error, string) {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		code, ok := fiberStatusCode[fiberErr.Code]
+		if !ok {
+			if fiberErr.Code >= fiber.StatusInternalServerError {
+				return fiber.StatusInternalServerError, apperror.Internal(err), ""
+			}
+			code = apperror.CodeBadRequest
+		}
+
+		// Pesan bawaan fiber ("Not Found", dst) diganti pesan katalog, pesan kustom dipertahankan
+		message := ""
+		if fiberErr.Message != fiberutils.StatusMessage(fiberErr.Code) || !i18n.Has(code) {
+			message = fiberErr.Message
+		}
+		return fiberErr.Code, &apperror.Error{Code: code, Err: err}, message
+	}
+
+	appErr, ok := apperror.As(apperror.FromDatabase(err))
+	if !ok {
+		appErr = apperror.Internal(err)
+	}
+
+	status, ok := kindStatus[appErr.Kind]
+	if !ok {
+		status = fiber.StatusInternalServerError
+	}
+	return status, appErr, ""
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"uas/helpers"
+	"uas/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language menentukan bahasa respons dari header Accept-Language.
+// Untuk request terautentikasi, preferensi bahasa user di token menggantikannya (lihat AuthRequired).
+func Language() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Vary(fiber.HeaderAcceptLanguage)
+		helpers.SetLang(c, i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage)))
+		return c.Next()
+	}
+}
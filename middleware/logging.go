@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+)
+
+// Panjang maksimal X-Request-ID dari client, selebihnya diganti ID baru
+const maxRequestIDLength = 128
+
+// RequestID menerima X-Request-ID dari client (atau membuat yang baru), mengembalikannya di response,
+// dan menyimpan logger ber-request_id ke context agar dapat dipakai service & repository.
+func RequestID(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Disalin karena dipakai logger & span di luar masa hidup buffer request fasthttp
+		id := fiberutils.CopyString(c.Get(fiber.HeaderXRequestID))
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Locals("request_id", id)
+		c.Set(fiber.HeaderXRequestID, id)
+		c.SetUserContext(utils.WithLogger(c.UserContext(), logger.With("request_id", id)))
+
+		return c.Next()
+	}
+}
+
+// AccessLog mencatat setiap request beserta user, role, route, status dan latensi.
+// Error dari handler diteruskan ke ErrorHandler lebih dulu agar status yang dicatat sesuai response.
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("route", c.Route().Path),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.String("ip", c.IP()),
+		}
+		if userID, ok := c.Locals("user_id").(uuid.UUID); ok {
+			attrs = append(attrs, slog.String("user_id", userID.String()))
+		}
+		if role, ok := c.Locals("role_name").(string); ok {
+			attrs = append(attrs, slog.String("role", role))
+		}
+
+		utils.Logger(c.UserContext()).LogAttrs(c.UserContext(), level, "http request", attrs...)
+		return nil
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
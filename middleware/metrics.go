@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"time"
+	"uas/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics mencatat jumlah dan latensi request per route template (bukan path asli,
+// agar kardinalitas label tetap kecil) dan status.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := responseStatus(c, err)
+
+		// c.Method() menunjuk buffer request yang dipakai ulang fasthttp; label Prometheus harus salinan
+		metrics.ObserveHTTP(utils.CopyString(c.Method()), c.Route().Path, status, time.Since(start))
+		return err
+	}
+}
+
+// MetricsHandler menyajikan registry aplikasi dalam format Prometheus
+func MetricsHandler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+}
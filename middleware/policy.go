@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/helpers"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ResourceResolver memetakan request ke pemilik resource target
+type ResourceResolver func(c *fiber.Ctx) (models.PolicyResource, error)
+
+// Authorizer mengecek permission user beserta scope-nya terhadap resource target
+type Authorizer struct {
+	repo repository.PolicyRepository
+}
+
+func NewAuthorizer(repo repository.PolicyRepository) *Authorizer {
+	return &Authorizer{repo: repo}
+}
+
+// Menerima parameter string 'perm' (misal: "achievement:create") dan resolver resource opsional.
+// Jika resolver diberikan, scope permission (own/advisees/department/all) dievaluasi terhadap resource target;
+// tanpa resolver hanya scope all yang diterima.
+func (a *Authorizer) RequirePermission(perm string, resolvers ...ResourceResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// 1. Ambil User ID dari Locals (yang diset oleh AuthRequired)
+		userIDLocal := c.Locals("user_id")
+
+		if userIDLocal == nil {
+			return apperror.Unauthorized(apperror.CodeUnauthorized)
+		}
+
+		// Pastikan tipe datanya UUID
+		userID, ok := userIDLocal.(uuid.UUID)
+		if !ok {
+			return apperror.Internal(fmt.Errorf("tipe data user_id tidak valid: %T", userIDLocal))
+		}
+
+		// 2. Cek ke Database via Repository
+		scope, err := a.repo.GetPermissionScope(c.UserContext(), userID.String(), perm)
+		if apperror.IsNotFound(err) {
+			return apperror.Forbidden(apperror.CodePermissionDenied).With("permission", perm)
+		} else if err != nil {
+			return fmt.Errorf("gagal memverifikasi izin %s: %w", perm, err)
+		}
+
+		// 3. Evaluasi policy terhadap resource target. Route tanpa resolver (daftar, export, aksi global)
+		// tidak dapat membatasi datanya per scope, sehingga hanya scope all yang diterima.
+		if scope != models.ScopeAll && len(resolvers) == 0 {
+			return apperror.Forbidden(apperror.CodeScopeDenied).
+				With("permission", perm).With("scope", scope)
+		}
+		if scope != models.ScopeAll {
+			subject, err := a.repo.GetSubject(c.UserContext(), userID.String())
+			if err != nil {
+				return fmt.Errorf("gagal memuat subject policy: %w", err)
+			}
+
+			for _, resolve := range resolvers {
+				resource, err := resolve(c)
+				if apperror.IsNotFound(err) {
+					return apperror.NotFound(apperror.CodeNotFound)
+				} else if err != nil {
+					return fmt.Errorf("gagal memuat resource policy: %w", err)
+				}
+
+				if !helpers.EvaluateScope(scope, subject, resource) {
+					return apperror.Forbidden(apperror.CodeScopeDenied).
+						With("permission", perm).With("scope", scope)
+				}
+			}
+		}
+
+		c.Locals("permission_scope", scope)
+
+		// 4. Lanjut ke Controller
+		return c.Next()
+	}
+}
+
+// resolveByParam memakai parameter :id sebagai ID resource
+func resolveByParam(load func(ctx context.Context, id string) (models.PolicyResource, error)) ResourceResolver {
+	return func(c *fiber.Ctx) (models.PolicyResource, error) {
+		id := c.Params("id")
+		if _, err := uuid.Parse(id); err != nil {
+			return models.PolicyResource{}, sql.ErrNoRows
+		}
+		return load(c.UserContext(), id)
+	}
+}
+
+// AchievementResource menggunakan parameter :id sebagai ID prestasi
+func AchievementResource(repo repository.PolicyRepository) ResourceResolver {
+	return resolveByParam(repo.GetAchievementResource)
+}
+
+// StudentResource menggunakan parameter :id sebagai ID mahasiswa
+func StudentResource(repo repository.PolicyRepository) ResourceResolver {
+	return resolveByParam(repo.GetStudentResource)
+}
+
+// LecturerResource menggunakan parameter :id sebagai ID dosen
+func LecturerResource(repo repository.PolicyRepository) ResourceResolver {
+	return resolveByParam(repo.GetLecturerResource)
+}
+
+// SelfResource dipakai route yang membuat resource atas nama user sendiri (misalnya prestasi baru),
+// sehingga scope own terpenuhi oleh profil mahasiswa/dosen user yang login
+func SelfResource(repo repository.PolicyRepository) ResourceResolver {
+	return func(c *fiber.Ctx) (models.PolicyResource, error) {
+		userID, _ := c.Locals("user_id").(uuid.UUID)
+		subject, err := repo.GetSubject(c.UserContext(), userID.String())
+		if err != nil {
+			return models.PolicyResource{}, err
+		}
+		return models.PolicyResource{
+			StudentID:  subject.StudentID,
+			LecturerID: subject.LecturerID,
+			Department: subject.Department,
+		}, nil
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing membuat span server untuk setiap request (melanjutkan traceparent dari client bila ada)
+// dan meneruskannya lewat UserContext, sehingga query PostgreSQL & MongoDB menjadi child span.
+func Tracing() fiber.Handler {
+	tracer := otel.Tracer("uas/http")
+
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+
+		// Span diekspor setelah request selesai, sedangkan string dari fiber menunjuk buffer yang dipakai ulang
+		method := fiberutils.CopyString(c.Method())
+		ctx, span := tracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.URLPath(fiberutils.CopyString(c.Path())),
+				semconv.ClientAddress(c.IP()),
+			),
+		)
+		defer span.End()
+
+		// trace_id ikut dicatat di log agar log & trace dapat dikorelasikan
+		if sc := span.SpanContext(); sc.IsValid() {
+			ctx = utils.WithLogger(ctx, utils.Logger(ctx).With("trace_id", sc.TraceID().String()))
+			span.SetAttributes(attribute.String("request.id", requestIDOf(c)))
+		}
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		status := responseStatus(c, err)
+
+		route := c.Route().Path
+		span.SetName(method + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if userID, ok := c.Locals("user_id").(uuid.UUID); ok {
+			span.SetAttributes(attribute.String("enduser.id", userID.String()))
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		if status >= 500 {
+			span.SetStatus(codes.Error, "")
+		}
+
+		return err
+	}
+}
+
+func requestIDOf(c *fiber.Ctx) string {
+	id, _ := c.Locals("request_id").(string)
+	return id
+}
+
+// headerCarrier menghubungkan header fasthttp dengan propagator OpenTelemetry
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key string, value string) {
+	h.c.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
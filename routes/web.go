@@ -1,59 +1,116 @@
 package routes
 
 import (
-	"database/sql"
-	"uas/app/repository"
-	"uas/app/services"
+	"uas/app/container"
+	"uas/docs"
 	"uas/middleware"
 
 	"github.com/gofiber/fiber/v2"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func SetupRoutes(app *fiber.App, postgreSQL *sql.DB, mongoDB *mongo.Database) {
+func SetupRoutes(app *fiber.App, c *container.Application) {
+
+	// Request ID, bahasa respons, tracing, access log & metrics untuk seluruh request
+	app.Use(middleware.RequestID(c.Logger), middleware.Language(), middleware.Tracing(), middleware.AccessLog(), middleware.Metrics())
+
+	// Probe orchestrator (tidak perlu login)
+	systemService := c.Services.System
+	app.Get("/healthz", systemService.Healthz)
+	app.Get("/readyz", systemService.Readyz)
+	app.Get("/metrics", middleware.MetricsHandler())
+
+	// Dokumentasi API (OpenAPI 3.1)
+	app.Get("/api/docs", docs.UIHandler)
+	app.Get(docs.SpecPath, docs.SpecHandler)
 
 	api := app.Group("/api/v1") // (tidak perlu login)
 
 	// Autentikasi & Otorisasi 
+	authService := c.Services.Auth
+	personalDataService := c.Services.PersonalData
 	auth := api.Group("/auth")
-	auth.Post("/login", services.Login)
-	auth.Post("/refresh", services.Refresh)
-	auth.Get("/profile", middleware.AuthRequired(), services.GetProfile)
+	auth.Post("/login", authService.Login)
+	auth.Post("/refresh", authService.Refresh)
+	auth.Get("/profile", middleware.AuthRequired(), authService.GetProfile)
+	auth.Put("/preferences", middleware.AuthRequired(), middleware.DenyImpersonation(), authService.UpdatePreferences)
+	auth.Post("/logout", middleware.AuthRequired(), authService.Logout)
+	auth.Get("/sessions", middleware.AuthRequired(), authService.GetSessions)
+	auth.Delete("/sessions/:id", middleware.AuthRequired(), authService.RevokeSession)
+	auth.Get("/personal-data", middleware.AuthRequired(), middleware.DenyImpersonation(), personalDataService.ExportOwnData)
 
 	// Protected routes (perlu login) 
 	protected := api.Group("", middleware.AuthRequired()) 
+
+	// Permission & policy resource (scope own/advisees/department/all)
+	authz := c.Authorizer
+	achievementResource := middleware.AchievementResource(c.Repositories.Policy)
+	studentResource := middleware.StudentResource(c.Repositories.Policy)
+	lecturerResource := middleware.LecturerResource(c.Repositories.Policy)
+	selfResource := middleware.SelfResource(c.Repositories.Policy)
 	
 	// Users (Admin)
-	userService := services.NewUserService(postgreSQL)
-	protected.Post("/users", middleware.RequirePermission("users:create"), userService.CreateUser)
-	protected.Get("/users", middleware.RequirePermission("users:read"), userService.GetAllUsers)
-	protected.Get("/users/:id", middleware.RequirePermission("users:read"), userService.GetUserByID)
-	protected.Put("/users/:id", middleware.RequirePermission("users:update"), userService.UpdateUser)
-	protected.Delete("/users/:id", middleware.RequirePermission("users:delete"), userService.DeleteUser)
-	protected.Put("/users/:id/role", userService.UpdateUserRole)
+	userService := c.Services.User
+	userImportService := c.Services.UserImport
+	protected.Post("/users", authz.RequirePermission("users:create"), userService.CreateUser)
+	protected.Get("/users", authz.RequirePermission("users:read"), userService.GetAllUsers)
+	protected.Get("/users/export", authz.RequirePermission("users:read"), userService.ExportUsers)
+	protected.Post("/users/import", authz.RequirePermission("users:create"), userImportService.ImportUsers)
+	protected.Get("/users/:id", authz.RequirePermission("users:read"), userService.GetUserByID)
+	protected.Put("/users/:id", middleware.DenyImpersonation(), authz.RequirePermission("users:update"), userService.UpdateUser)
+	protected.Delete("/users/:id", authz.RequirePermission("users:delete"), userService.DeleteUser)
+	protected.Post("/users/:id/restore", authz.RequirePermission("users:delete"), userService.RestoreUser)
+	protected.Get("/users/:id/personal-data", authz.RequirePermission("users:personal_data"), personalDataService.ExportUserData)
+	protected.Put("/users/:id/role", middleware.DenyImpersonation(), authz.RequirePermission("users:update"), userService.UpdateUserRole)
+	protected.Post("/users/:id/logout", authz.RequirePermission("users:update"), authService.ForceLogout)
+	protected.Post("/users/:id/impersonate", middleware.DenyImpersonation(), authz.RequirePermission("users:impersonate"), userService.ImpersonateUser)
+
+	// Roles & Permissions (Admin)
+	roleService := c.Services.Role
+	protected.Get("/roles", authz.RequirePermission("roles:read"), roleService.GetRoles)
+	protected.Get("/roles/:id", authz.RequirePermission("roles:read"), roleService.GetRoleByID)
+	protected.Post("/roles", middleware.DenyImpersonation(), authz.RequirePermission("roles:create"), roleService.CreateRole)
+	protected.Put("/roles/:id", middleware.DenyImpersonation(), authz.RequirePermission("roles:update"), roleService.UpdateRole)
+	protected.Delete("/roles/:id", middleware.DenyImpersonation(), authz.RequirePermission("roles:delete"), roleService.DeleteRole)
+	protected.Post("/roles/:id/permissions", middleware.DenyImpersonation(), authz.RequirePermission("roles:update"), roleService.GrantPermission)
+	protected.Delete("/roles/:id/permissions/:permissionId", middleware.DenyImpersonation(), authz.RequirePermission("roles:update"), roleService.RevokePermission)
+	protected.Get("/permissions", authz.RequirePermission("roles:read"), roleService.GetPermissions)
 
 	// Students (Admin)
-	studentRepo := repository.NewStudentRepository(postgreSQL)
-	studentService := services.NewStudentService(studentRepo)
-	protected.Get("/students", middleware.RequirePermission("students:read"), studentService.GetStudents)
-	protected.Get("/students/:id", middleware.RequirePermission("students:read"), studentService.GetStudentByID)
-	protected.Put("/students/:id/advisor", middleware.RequirePermission("students:update"), studentService.UpdateStudentAdvisor)
+	studentService := c.Services.Student
+	protected.Get("/students", authz.RequirePermission("students:read"), studentService.GetStudents)
+	protected.Get("/students/export", authz.RequirePermission("students:read"), studentService.ExportStudents)
+	protected.Get("/students/:id", authz.RequirePermission("students:read", studentResource), studentService.GetStudentByID)
+	protected.Put("/students/:id", authz.RequirePermission("students:update", studentResource), studentService.UpdateStudent)
+	protected.Put("/students/:id/advisor", authz.RequirePermission("students:update", studentResource), studentService.UpdateStudentAdvisor)
+	protected.Put("/students/:id/status", authz.RequirePermission("students:update", studentResource), studentService.UpdateStudentStatus)
+	protected.Get("/students/:id/status-history", authz.RequirePermission("students:read", studentResource), studentService.GetStudentStatusHistory)
 
 	// Lectures (Admin)
-	lecturerRepo := repository.NewLecturerRepository(postgreSQL)
-  lecturerService := services.NewLecturerService(lecturerRepo)
-	protected.Get("/lecturers", middleware.RequirePermission("lecturers:read"), lecturerService.GetLecturers)
-	protected.Get("/lecturers/:id/advisees", middleware.RequirePermission("lecturers:read"), lecturerService.GetLecturerAdvisees)
+	lecturerService := c.Services.Lecturer
+	protected.Get("/lecturers", authz.RequirePermission("lecturers:read"), lecturerService.GetLecturers)
+	protected.Get("/lecturers/export", authz.RequirePermission("lecturers:read"), lecturerService.ExportLecturers)
+	protected.Post("/lecturers", authz.RequirePermission("lecturers:create"), lecturerService.CreateLecturer)
+	protected.Get("/lecturers/:id", authz.RequirePermission("lecturers:read", lecturerResource), lecturerService.GetLecturerByID)
+	protected.Put("/lecturers/:id", authz.RequirePermission("lecturers:update", lecturerResource), lecturerService.UpdateLecturer)
+	protected.Get("/lecturers/:id/advisees", authz.RequirePermission("lecturers:read", lecturerResource), lecturerService.GetLecturerAdvisees)
+	protected.Post("/lecturers/:id/advisees/reassign", authz.RequirePermission("lecturers:update", lecturerResource), lecturerService.ReassignAdvisees)
 
 	// Achievements (Mahasiswa)
-	achRepo := repository.NewAchievementRepository(postgreSQL, mongoDB)
-	achService := services.NewAchievementService(achRepo)
-	protected.Post("/achievements", middleware.RequirePermission("achievements:create"), achService.CreateAchievement)
-	protected.Put("/achievements/:id", middleware.RequirePermission("achievements:update"), achService.UpdateAchievement)
-	protected.Delete("/achievements/:id", middleware.RequirePermission("achievements:delete"), achService.DeleteAchievement)
-	protected.Post("/achievements/:id/submit", middleware.RequirePermission("achievements:update"), achService.SubmitAchievement)
+	achService := c.Services.Achievement
+	protected.Post("/achievements", authz.RequirePermission("achievements:create", selfResource), achService.CreateAchievement)
+	protected.Put("/achievements/:id", authz.RequirePermission("achievements:update", achievementResource), achService.UpdateAchievement)
+	protected.Delete("/achievements/:id", authz.RequirePermission("achievements:delete", achievementResource), achService.DeleteAchievement)
+	protected.Post("/achievements/:id/submit", authz.RequirePermission("achievements:update", achievementResource), achService.SubmitAchievement)
 
 	// Achievements (Dosen Wali)
-	protected.Post("/achievements/:id/verify", middleware.RequirePermission("achievements:verify"), achService.VerifyAchievement)
-	protected.Post("/achievements/:id/reject", middleware.RequirePermission("achievements:reject"), achService.RejectAchievement)
+	protected.Post("/achievements/:id/verify", authz.RequirePermission("achievements:verify", achievementResource), achService.VerifyAchievement)
+	protected.Post("/achievements/:id/reject", authz.RequirePermission("achievements:reject", achievementResource), achService.RejectAchievement)
+
+	// Audit Log (Admin)
+	auditService := c.Services.Audit
+	protected.Get("/audit", authz.RequirePermission("audit:read"), auditService.GetAuditLogs)
+	protected.Get("/audit/verify", authz.RequirePermission("audit:read"), auditService.VerifyAuditChain)
+
+	// System (Admin)
+	protected.Get("/system/status", authz.RequirePermission("system:read"), systemService.GetStatus)
 }
\ No newline at end of file
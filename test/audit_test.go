@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/helpers"
+	"uas/utils"
+
+	"github.com/google/uuid"
+)
+
+func TestHashAuditEntryDetectsTampering(t *testing.T) {
+	entry := models.AuditLog{
+		ActorID:    "11111111-1111-1111-1111-111111111111",
+		Action:     models.AuditUserDelete,
+		TargetType: "user",
+		TargetID:   "22222222-2222-2222-2222-222222222222",
+		Before:     json.RawMessage(`{"username": "budi", "is_active": true}`),
+		CreatedAt:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	entry.Hash = utils.HashAuditEntry(entry)
+
+	// JSONB bisa mengubah urutan key dan spasi, hash harus tetap sama
+	reread := entry
+	reread.Before = json.RawMessage(`{"is_active":true,"username":"budi"}`)
+	if utils.HashAuditEntry(reread) != entry.Hash {
+		t.Fatal("hash berubah hanya karena format JSON berbeda")
+	}
+
+	tampered := entry
+	tampered.TargetID = "33333333-3333-3333-3333-333333333333"
+	if utils.HashAuditEntry(tampered) == entry.Hash {
+		t.Fatal("perubahan target_id tidak terdeteksi oleh hash")
+	}
+
+	next := models.AuditLog{Action: models.AuditUserCreate, TargetType: "user", PrevHash: entry.Hash, CreatedAt: entry.CreatedAt}
+	relinked := next
+	relinked.PrevHash = utils.HashAuditEntry(tampered)
+	if utils.HashAuditEntry(next) == utils.HashAuditEntry(relinked) {
+		t.Fatal("hash entry berikutnya harus bergantung pada prev_hash")
+	}
+}
+
+func TestHashAuditEntryVerifiesRedactedEntry(t *testing.T) {
+	entry := models.AuditLog{
+		ActorID:       "11111111-1111-1111-1111-111111111111",
+		ActorUsername: "budi",
+		Action:        models.AuditUserUpdate,
+		TargetType:    "user",
+		TargetID:      "22222222-2222-2222-2222-222222222222",
+		After:         json.RawMessage(`{"email":"budi@unair.ac.id"}`),
+		IP:            "10.0.0.1",
+		UserAgent:     "Mozilla/5.0",
+		PrevHash:      "abc",
+		CreatedAt:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	entry.PIIHash = utils.HashAuditPII(entry)
+	entry.Hash = utils.HashAuditEntry(entry)
+
+	// Redaksi mengosongkan kolom data pribadi tetapi hash tetap dapat dihitung ulang
+	redacted := entry
+	redacted.ActorUsername, redacted.IP, redacted.UserAgent, redacted.After = "", "", "", nil
+	if utils.HashAuditEntry(redacted) != entry.Hash {
+		t.Fatal("hash entry yang diredaksi tidak dapat dihitung ulang")
+	}
+	if utils.HashAuditPII(redacted) == entry.PIIHash {
+		t.Fatal("pii_hash harus berubah jika kolom data pribadi berubah")
+	}
+
+	tampered := redacted
+	tampered.Action = models.AuditUserDelete
+	if utils.HashAuditEntry(tampered) == entry.Hash {
+		t.Fatal("perubahan action pada entry yang diredaksi tidak terdeteksi")
+	}
+}
+
+func TestDiffAuditOnlyKeepsChangedFields(t *testing.T) {
+	before := models.UpdateUser{Username: "budi", Email: "budi@unair.ac.id", IsActive: true}
+	after := models.UpdateUser{Username: "budi", Email: "budi.baru@unair.ac.id", IsActive: true}
+
+	b, a := helpers.DiffAudit(before, after)
+	if string(b) != `{"email":"budi@unair.ac.id"}` {
+		t.Fatalf("before = %s", b)
+	}
+	if string(a) != `{"email":"budi.baru@unair.ac.id"}` {
+		t.Fatalf("after = %s", a)
+	}
+}
+
+type fakeAuditListRepo struct {
+	repository.AuditRepository
+	filter models.AuditFilter
+}
+
+func (f *fakeAuditListRepo) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditLog, int, error) {
+	f.filter = filter
+	return nil, 0, nil
+}
+
+func TestGetAuditLogsValidatesQuery(t *testing.T) {
+	audit := &fakeAuditListRepo{}
+	app, token := newFakeAppWithRepos(t, container.Repositories{
+		Audit:  audit,
+		Policy: &fakePolicyRepo{granted: map[string]string{"audit:read": models.ScopeAll}},
+	})
+
+	do := func(query string) (int, string) {
+		req := httptest.NewRequest("GET", "/api/v1/audit"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out struct {
+			Error struct {
+				Code   string
+				Params map[string]interface{}
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		param, _ := out.Error.Params["param"].(string)
+		return resp.StatusCode, out.Error.Code + ":" + param
+	}
+
+	for _, param := range []string{"actor_id", "impersonator_id", "page", "limit"} {
+		if status, got := do("?" + param + "=abc"); status != 400 || got != apperror.CodeInvalidQuery+":"+param {
+			t.Errorf("%s tidak valid: status = %d, error = %s", param, status, got)
+		}
+	}
+
+	actor := uuid.NewString()
+	if status, _ := do("?actor_id=" + actor + "&limit=500"); status != 200 {
+		t.Fatalf("query valid: status = %d", status)
+	}
+	if audit.filter.ActorID != actor || audit.filter.Limit != models.MaxListLimit {
+		t.Errorf("filter = %+v, want actor %s dan limit %d", audit.filter, actor, models.MaxListLimit)
+	}
+}
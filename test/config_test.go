@@ -0,0 +1,64 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"uas/config"
+)
+
+func envFrom(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestConfigFromEnvDefaults(t *testing.T) {
+	cfg, err := config.FromEnv(envFrom(map[string]string{
+		"POSTGRES_URI": "postgres://localhost/uas",
+		"MONGO_URI":    "mongodb://localhost:27017",
+		"JWT_SECRET":   strings.Repeat("x", config.MinJWTSecretLength),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.App.Addr() != ":3000" {
+		t.Errorf("Addr = %q, want :3000", cfg.App.Addr())
+	}
+	if cfg.Mongo.Database != "uas" {
+		t.Errorf("Mongo.Database = %q, want uas", cfg.Mongo.Database)
+	}
+	if cfg.JWT.AccessTTL != 15*time.Minute {
+		t.Errorf("JWT.AccessTTL = %v, want 15m", cfg.JWT.AccessTTL)
+	}
+}
+
+func TestConfigFromEnvLegacyPostgresKey(t *testing.T) {
+	cfg, err := config.FromEnv(envFrom(map[string]string{
+		"POSGRES_URI": "postgres://legacy/uas",
+		"MONGO_URI":   "mongodb://localhost:27017",
+		"JWT_SECRET":  strings.Repeat("x", config.MinJWTSecretLength),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Postgres.URI != "postgres://legacy/uas" {
+		t.Errorf("Postgres.URI = %q", cfg.Postgres.URI)
+	}
+}
+
+func TestConfigFromEnvReportsAllProblems(t *testing.T) {
+	_, err := config.FromEnv(envFrom(map[string]string{
+		"APP_PORT":   "abc",
+		"JWT_SECRET": "pendek",
+	}))
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{"APP_PORT", "POSTGRES_URI", "MONGO_URI", "JWT_SECRET minimal 32"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error tidak menyebut %q:\n%s", want, msg)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/config"
+	"uas/middleware"
+	"uas/routes"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Repository palsu: method yang tidak di-override akan panic karena interface-nya nil
+type fakeUserRepo struct {
+	repository.UserRepository
+	users []models.User
+}
+
+func (f *fakeUserRepo) ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, models.ListMeta, error) {
+	return f.users, models.ListMeta{Limit: filter.Limit, Total: len(f.users)}, nil
+}
+
+func (f *fakeUserRepo) ExportUsers(ctx context.Context, filter models.UserFilter, fn func(models.User) error) error {
+	for _, user := range f.users {
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakePolicyRepo struct {
+	repository.PolicyRepository
+	granted map[string]string
+}
+
+func (f *fakePolicyRepo) GetPermissionScope(ctx context.Context, userID string, permissionName string) (string, error) {
+	scope, ok := f.granted[permissionName]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return scope, nil
+}
+
+func newFakeApp(t *testing.T, policy *fakePolicyRepo) (*fiber.App, string) {
+	return newFakeAppWithRepos(t, container.Repositories{
+		User:   &fakeUserRepo{users: []models.User{{ID: uuid.New(), Username: "admin"}}},
+		Policy: policy,
+	})
+}
+
+// newFakeAppWithRepos menyusun aplikasi dari repository palsu dan token Admin untuk request
+func newFakeAppWithRepos(t *testing.T, repos container.Repositories) (*fiber.App, string) {
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Secret:     strings.Repeat("x", config.MinJWTSecretLength),
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	}}
+	utils.ConfigureJWT(cfg.JWT)
+
+	application := container.Build(cfg, repos)
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	routes.SetupRoutes(app, application)
+
+	token, err := utils.GenerateToken(models.User{ID: uuid.New(), Username: "admin", RoleName: "Admin"}, uuid.NewString())
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	return app, token
+}
+
+func TestContainerWiresFakeRepositories(t *testing.T) {
+	cases := []struct {
+		name    string
+		granted map[string]string
+		want    int
+	}{
+		{"punya izin", map[string]string{"users:read": models.ScopeAll}, 200},
+		{"tanpa izin", map[string]string{}, 403},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app, token := newFakeApp(t, &fakePolicyRepo{granted: tc.granted})
+
+			req := httptest.NewRequest("GET", "/api/v1/users", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if resp.StatusCode != tc.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.want)
+			}
+		})
+	}
+}
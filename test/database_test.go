@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"uas/config"
 	"uas/database"
@@ -8,23 +9,37 @@ import (
 
 func TestDatabasePostgreSQL(t *testing.T) {
 	// Menghubungkan ENV
-	config.ConfigTest();
+	cfg, err := config.Load("../.env")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	postgreSQLDB := database.ConnectDB()
+	postgreSQLDB, err := database.ConnectDB(context.Background(), cfg.Postgres)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Cek apakah koneksi error
 	if postgreSQLDB == nil {
 		t.Fatal("Gagal menghubungkan ke database PostgreSQL")
 	}
 
-	err := postgreSQLDB.Ping()
+	err = postgreSQLDB.Ping()
 	if err != nil {
 		t.Fatalf("Failed to ping PostgreSQL: %v", err)
 	}
 }
 
 func TestDatabaseMongoDB(t *testing.T) {
-	mongoDB := database.ConnectMongoDB()
+	cfg, err := config.Load("../.env")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mongoDB, err := database.ConnectMongoDB(context.Background(), cfg.Mongo)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Cek apakah koneksi error
 	if mongoDB == nil {
@@ -36,4 +51,3 @@ func TestDatabaseMongoDB(t *testing.T) {
 	// 	t.Fatalf("Failed to ping MongoDB: %v", err)
 	// }
 }
-
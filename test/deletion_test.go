@@ -0,0 +1,252 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+
+	"github.com/google/uuid"
+)
+
+// fakeDeletionUserRepo menyimpan user beserta jumlah data terkaitnya
+type fakeDeletionUserRepo struct {
+	repository.UserRepository
+	users map[uuid.UUID]models.User
+	deps  map[uuid.UUID]models.UserDependencies
+	// anonymizeAudit adalah entry audit yang diserahkan ke repository untuk ditulis bersama anonimisasi
+	anonymizeAudit []models.AuditLog
+}
+
+func (f *fakeDeletionUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return u, apperror.Wrap(sql.ErrNoRows, apperror.NotFound(apperror.CodeUserNotFound))
+	}
+	return u, nil
+}
+
+func (f *fakeDeletionUserRepo) SoftDeleteUser(ctx context.Context, id uuid.UUID) error {
+	u := f.users[id]
+	if u.DeletedAt != nil {
+		return apperror.Conflict(apperror.CodeUserDeleted)
+	}
+	now := time.Now()
+	u.IsActive, u.DeletedAt = false, &now
+	f.users[id] = u
+	return nil
+}
+
+func (f *fakeDeletionUserRepo) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	u := f.users[id]
+	if u.DeletedAt == nil {
+		return apperror.Conflict(apperror.CodeUserNotDeleted)
+	}
+	u.IsActive, u.DeletedAt = true, nil
+	f.users[id] = u
+	return nil
+}
+
+func (f *fakeDeletionUserRepo) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	if deps := f.deps[id]; deps.Blocking() {
+		return apperror.Conflict(apperror.CodeUserHasDependencies).
+			With("achievements", deps.Achievements).With("advisees", deps.Advisees).With("verifications", deps.Verifications)
+	}
+	delete(f.users, id)
+	return nil
+}
+
+func (f *fakeDeletionUserRepo) AnonymizeUser(ctx context.Context, id uuid.UUID, entry models.AuditLog) error {
+	if f.deps[id].Advisees > 0 {
+		return apperror.Conflict(apperror.CodeUserHasDependencies)
+	}
+	f.anonymizeAudit = append(f.anonymizeAudit, entry)
+	now := time.Now()
+	u := f.users[id]
+	u.Username, u.Email, u.FullName = "anon_1", "anon_1@anonymized.invalid", "Pengguna Anonim"
+	u.IsActive, u.DeletedAt, u.AnonymizedAt = false, &now, &now
+	f.users[id] = u
+	return nil
+}
+
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	revoked []string
+}
+
+func (f *fakeSessionRepo) GetActiveSessionsByUserID(ctx context.Context, userID string) ([]models.Session, error) {
+	return nil, nil
+}
+
+func (f *fakeSessionRepo) RevokeSessionsByUserID(ctx context.Context, userID string, revokedBy string) ([]string, error) {
+	f.revoked = append(f.revoked, userID)
+	return nil, nil
+}
+
+func TestDeleteUserModes(t *testing.T) {
+	student, lecturer := uuid.New(), uuid.New()
+
+	newApp := func(t *testing.T) (*fakeDeletionUserRepo, *fakeSessionRepo, func(method, path string) (int, map[string]interface{})) {
+		users := &fakeDeletionUserRepo{
+			users: map[uuid.UUID]models.User{
+				student:  {ID: student, Username: "andi", Email: "andi@unair.ac.id", FullName: "Andi Pratama", IsActive: true},
+				lecturer: {ID: lecturer, Username: "budi", Email: "budi@unair.ac.id", FullName: "Budi Santoso", IsActive: true},
+			},
+			deps: map[uuid.UUID]models.UserDependencies{
+				student:  {Achievements: 2},
+				lecturer: {Advisees: 3, Verifications: 5},
+			},
+		}
+		sessions := &fakeSessionRepo{}
+		app, token := newFakeAppWithRepos(t, container.Repositories{
+			User:    users,
+			Role:    &fakeRoleRepo{},
+			Session: sessions,
+			Audit:   &fakeAuditRepo{},
+			Policy:  &fakePolicyRepo{granted: map[string]string{"users:delete": models.ScopeAll}},
+		})
+
+		return users, sessions, func(method, path string) (int, map[string]interface{}) {
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			var out map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&out)
+			return resp.StatusCode, out
+		}
+	}
+
+	errorOf := func(out map[string]interface{}) (string, map[string]interface{}) {
+		e, _ := out["error"].(map[string]interface{})
+		code, _ := e["code"].(string)
+		params, _ := e["params"].(map[string]interface{})
+		return code, params
+	}
+
+	t.Run("soft delete default lalu restore", func(t *testing.T) {
+		users, sessions, do := newApp(t)
+		path := "/api/v1/users/" + student.String()
+
+		if status, _ := do("DELETE", path+"?mode=purge"); status != 400 {
+			t.Errorf("mode tidak dikenal: status = %d, want 400", status)
+		}
+
+		status, _ := do("DELETE", path)
+		if u := users.users[student]; status != 200 || u.DeletedAt == nil || u.IsActive || len(sessions.revoked) != 1 {
+			t.Fatalf("soft delete: status = %d, user = %+v, revoked = %v", status, u, sessions.revoked)
+		}
+		if status, out := do("DELETE", path); status != 409 {
+			t.Errorf("soft delete ulang: status = %d, body = %v", status, out)
+		}
+
+		if status, _ := do("POST", path+"/restore"); status != 200 || !users.users[student].IsActive {
+			t.Errorf("restore: status = %d, user = %+v", status, users.users[student])
+		}
+	})
+
+	t.Run("hard delete ditolak selama ada data terkait", func(t *testing.T) {
+		users, _, do := newApp(t)
+
+		status, out := do("DELETE", "/api/v1/users/"+lecturer.String()+"?mode=hard")
+		code, params := errorOf(out)
+		if status != 409 || code != apperror.CodeUserHasDependencies || params["advisees"] != float64(3) {
+			t.Fatalf("hard delete: status = %d, code = %s, params = %v", status, code, params)
+		}
+		if _, ok := users.users[lecturer]; !ok {
+			t.Error("user dengan data terkait tidak boleh terhapus")
+		}
+
+		users.deps[lecturer] = models.UserDependencies{}
+		if status, _ := do("DELETE", "/api/v1/users/"+lecturer.String()+"?mode=hard"); status != 200 {
+			t.Errorf("hard delete tanpa data terkait: status = %d", status)
+		}
+		if _, ok := users.users[lecturer]; ok {
+			t.Error("user seharusnya terhapus permanen")
+		}
+	})
+
+	t.Run("anonymize mempertahankan baris user", func(t *testing.T) {
+		users, _, do := newApp(t)
+		path := "/api/v1/users/" + student.String()
+
+		status, _ := do("DELETE", path+"?mode=anonymize")
+		if u := users.users[student]; status != 200 || u.AnonymizedAt == nil || u.Email == "andi@unair.ac.id" {
+			t.Fatalf("anonymize: status = %d, user = %+v", status, u)
+		}
+		if len(users.anonymizeAudit) != 1 || users.anonymizeAudit[0].Action != models.AuditUserAnonymize ||
+			users.anonymizeAudit[0].Before != nil || strings.Contains(string(users.anonymizeAudit[0].After), "andi") {
+			t.Errorf("audit anonimisasi harus ditulis repository tanpa data pribadi: %+v", users.anonymizeAudit)
+		}
+
+		if status, out := do("POST", path+"/restore"); status != 409 {
+			t.Errorf("restore user anonim: status = %d, body = %v", status, out)
+		} else if code, _ := errorOf(out); code != apperror.CodeUserAnonymized {
+			t.Errorf("restore user anonim: code = %s", code)
+		}
+
+		if status, _ := do("DELETE", "/api/v1/users/"+lecturer.String()+"?mode=anonymize"); status != 409 {
+			t.Errorf("anonymize dosen dengan mahasiswa bimbingan: status = %d, want 409", status)
+		}
+	})
+}
+
+func TestDeleteUserKeepsLastAdmin(t *testing.T) {
+	admin := uuid.New()
+	users := &fakeDeletionUserRepo{users: map[uuid.UUID]models.User{
+		admin: {ID: admin, Username: "admin", Email: "admin@unair.ac.id", FullName: "Admin", RoleID: uuid.New(), IsActive: true},
+	}}
+	roles := &fakeRoleRepo{
+		permissions: []models.Permission{{Name: "users:read"}, {Name: "users:delete"}},
+		admins:      []string{admin.String()},
+	}
+	app, token := newFakeAppWithRepos(t, container.Repositories{
+		User:    users,
+		Role:    roles,
+		Session: &fakeSessionRepo{},
+		Audit:   &fakeAuditRepo{},
+		Policy:  &fakePolicyRepo{granted: map[string]string{"users:delete": models.ScopeAll}},
+	})
+
+	for _, mode := range []string{models.DeleteSoft, models.DeleteHard, models.DeleteAnonymize} {
+		req := httptest.NewRequest("DELETE", "/api/v1/users/"+admin.String()+"?mode="+mode, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		if resp.StatusCode != 409 || out.Error.Code != apperror.CodeLastAdminPermission {
+			t.Errorf("mode %s: status = %d, code = %s, want 409 %s", mode, resp.StatusCode, out.Error.Code, apperror.CodeLastAdminPermission)
+		}
+	}
+	if u, ok := users.users[admin]; !ok || u.DeletedAt != nil || u.AnonymizedAt != nil {
+		t.Errorf("admin terakhir tidak boleh terhapus: %+v", u)
+	}
+
+	// Jika masih ada admin aktif lain, penghapusan diizinkan
+	roles.admins = append(roles.admins, uuid.NewString())
+	req := httptest.NewRequest("DELETE", "/api/v1/users/"+admin.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("soft delete dengan admin lain: status = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/config"
+	"uas/middleware"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+func TestErrorHandlerEnvelope(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantFields int
+	}{
+		{"not found", apperror.NotFound(apperror.CodeUserNotFound), 404, apperror.CodeUserNotFound, 0},
+		{"validation", apperror.Validation(apperror.FieldError{Field: "email", Code: "email", Message: "Email tidak valid"}), 400, apperror.CodeValidation, 1},
+		{"dibungkus", fmt.Errorf("service: %w", apperror.Forbidden(apperror.CodePermissionDenied)), 403, apperror.CodePermissionDenied, 0},
+		{"unique violation", fmt.Errorf("insert: %w", &pq.Error{Code: "23505", Message: "duplicate key users_email_key"}), 409, apperror.CodeDuplicateValue, 0},
+		{"fiber route", fiber.ErrNotFound, 404, apperror.CodeRouteNotFound, 0},
+		{"tidak dikenal", errors.New("pq: connection refused 10.0.0.5"), 500, apperror.CodeInternal, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := utils.NewLogger(config.LogConfig{Level: "error", Format: "json"}, &strings.Builder{})
+
+			app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+			app.Use(middleware.RequestID(logger))
+			app.Get("/", func(c *fiber.Ctx) error { return tc.err })
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(fiber.HeaderXRequestID, "req-err")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+
+			var body models.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Success || body.Error.Code != tc.wantCode || len(body.Error.Fields) != tc.wantFields {
+				t.Errorf("body = %+v", body)
+			}
+			if body.RequestID != "req-err" {
+				t.Errorf("request_id = %q, want req-err", body.RequestID)
+			}
+			// Detail error database/internal tidak boleh bocor ke client
+			if strings.Contains(body.Error.Message, "10.0.0.5") || strings.Contains(body.Error.Message, "users_email_key") {
+				t.Errorf("pesan membocorkan detail internal: %q", body.Error.Message)
+			}
+		})
+	}
+}
+
+func TestAppErrorIs(t *testing.T) {
+	err := fmt.Errorf("repo: %w", apperror.NotFound(apperror.CodeRoleNotFound))
+
+	if !errors.Is(err, apperror.ErrNotFound) || !apperror.IsNotFound(err) {
+		t.Error("error not found tidak dikenali")
+	}
+	if errors.Is(err, apperror.ErrConflict) {
+		t.Error("error not found dikenali sebagai conflict")
+	}
+	if !errors.Is(err, apperror.NotFound(apperror.CodeRoleNotFound)) || errors.Is(err, apperror.NotFound(apperror.CodeUserNotFound)) {
+		t.Error("pencocokan berdasarkan kode tidak sesuai")
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/container"
+	"uas/app/models"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportUsers(t *testing.T) {
+	users := []models.User{
+		{ID: uuid.New(), Username: "andi_mhs", Email: "andi@student.unair.ac.id", FullName: "Andi Pratama", RoleName: models.RoleMahasiswa, IsActive: true},
+		{ID: uuid.New(), Username: "budi", Email: "budi@unair.ac.id", FullName: "Budi, S.Kom", RoleName: models.RoleDosen},
+	}
+
+	get := func(t *testing.T, query string) (int, string, []byte) {
+		t.Helper()
+		audit := &fakeAuditRepo{}
+		app, token := newFakeAppWithRepos(t, container.Repositories{
+			User:   &fakeUserRepo{users: users},
+			Audit:  audit,
+			Policy: &fakePolicyRepo{granted: map[string]string{"users:read": models.ScopeAll}},
+		})
+
+		req := httptest.NewRequest("GET", "/api/v1/users/export"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == 200 && (len(audit.entries) != 1 || audit.entries[0].Action != models.AuditDataExport) {
+			t.Errorf("audit = %+v, want satu entri %s", audit.entries, models.AuditDataExport)
+		}
+		return resp.StatusCode, resp.Header.Get("Content-Disposition"), body
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		status, disposition, body := get(t, "?columns=full_name,username")
+		if status != 200 || !strings.Contains(disposition, `filename="users-`) {
+			t.Fatalf("status = %d, disposition = %q", status, disposition)
+		}
+		records, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+		if err != nil {
+			t.Fatalf("csv: %v", err)
+		}
+		want := [][]string{{"full_name", "username"}, {"Andi Pratama", "andi_mhs"}, {"Budi, S.Kom", "budi"}}
+		if len(records) != len(want) {
+			t.Fatalf("records = %v, want %v", records, want)
+		}
+		for i := range want {
+			if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+				t.Errorf("baris %d = %v, want %v", i, records[i], want[i])
+			}
+		}
+	})
+
+	t.Run("ndjson dengan masking", func(t *testing.T) {
+		status, _, body := get(t, "?format=ndjson&columns=email,full_name,is_active&mask_pii=true")
+		if status != 200 {
+			t.Fatalf("status = %d", status)
+		}
+		scanner := bufio.NewScanner(strings.NewReader(string(body)))
+		scanner.Scan()
+		if got := scanner.Text(); got != `{"email":"a***@student.unair.ac.id","full_name":"A*** P***","is_active":true}` {
+			t.Errorf("baris pertama = %s", got)
+		}
+		lines := 1
+		for scanner.Scan() {
+			var row map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				t.Errorf("baris bukan JSON: %s", scanner.Text())
+			}
+			lines++
+		}
+		if lines != len(users) {
+			t.Errorf("jumlah baris = %d, want %d", lines, len(users))
+		}
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		status, _, body := get(t, "?format=xlsx")
+		if status != 200 {
+			t.Fatalf("status = %d", status)
+		}
+		wb, err := excelize.OpenReader(strings.NewReader(string(body)))
+		if err != nil {
+			t.Fatalf("xlsx: %v", err)
+		}
+		rows, _ := wb.GetRows("Sheet1")
+		if len(rows) != len(users)+1 || rows[0][1] != "username" || rows[1][1] != "andi_mhs" {
+			t.Errorf("rows = %v", rows)
+		}
+	})
+
+	t.Run("kolom tidak dikenal", func(t *testing.T) {
+		if status, _, _ := get(t, "?columns=password_hash"); status != 400 {
+			t.Errorf("status = %d, want 400", status)
+		}
+	})
+}
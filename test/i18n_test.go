@@ -0,0 +1,111 @@
+package test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/i18n"
+	"uas/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	cases := map[string]i18n.Lang{
+		"":                          i18n.ID,
+		"en":                        i18n.EN,
+		"en-US,en;q=0.9":            i18n.EN,
+		"fr-FR, en;q=0.5, id;q=0.8": i18n.ID,
+		"id;q=0.2, en-GB;q=0.7":     i18n.EN,
+		"en;q=0, de":                i18n.ID,
+		"ja":                        i18n.Default,
+	}
+	for header, want := range cases {
+		if got := i18n.Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	idKeys := strings.Join(i18n.Keys(i18n.ID), "\n")
+	enKeys := strings.Join(i18n.Keys(i18n.EN), "\n")
+	if idKeys != enKeys {
+		t.Error("kunci katalog id dan en tidak sama")
+	}
+
+	// Setiap kode error pada codes.go wajib memiliki pesan di katalog
+	file, err := parser.ParseFile(token.NewFileSet(), "../app/apperror/codes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		code, _ := strconv.Unquote(lit.Value)
+		if !i18n.Has(code) {
+			t.Errorf("kode %s tidak ada di katalog", code)
+		}
+		return true
+	})
+
+	for _, code := range []string{apperror.CodeBadRequest, apperror.CodeValidation, apperror.CodeUnauthorized,
+		apperror.CodeForbidden, apperror.CodeNotFound, apperror.CodeConflict, apperror.CodeInternal} {
+		if !i18n.Has(code) {
+			t.Errorf("kode %s tidak ada di katalog", code)
+		}
+	}
+}
+
+func TestErrorHandlerLocalized(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(middleware.Language())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return apperror.Forbidden(apperror.CodePermissionDenied).With("permission", "users:read")
+	})
+	app.Post("/", func(c *fiber.Ctx) error {
+		return apperror.Validation(apperror.FieldError{Field: "title", Code: "max_length", Params: map[string]interface{}{"param": 200}})
+	})
+
+	cases := []struct {
+		method, lang, wantMessage, wantField string
+	}{
+		{"GET", "en-US,en;q=0.9", "You do not have the 'users:read' permission", ""},
+		{"GET", "", "Anda tidak memiliki izin 'users:read'", ""},
+		{"POST", "en", "The submitted data is invalid", "Must be at most 200 characters"},
+		{"POST", "id", "Data yang dikirim tidak valid", "Maksimal 200 karakter"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, "/", nil)
+		if tc.lang != "" {
+			req.Header.Set(fiber.HeaderAcceptLanguage, tc.lang)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body models.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Message != tc.wantMessage {
+			t.Errorf("%s %q: message = %q, want %q", tc.method, tc.lang, body.Message, tc.wantMessage)
+		}
+		if tc.wantField != "" && (len(body.Error.Fields) != 1 || body.Error.Fields[0].Message != tc.wantField) {
+			t.Errorf("%s %q: fields = %+v, want %q", tc.method, tc.lang, body.Error.Fields, tc.wantField)
+		}
+		if resp.Header.Get(fiber.HeaderContentLanguage) == "" {
+			t.Error("header Content-Language tidak diset")
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+
+	"github.com/google/uuid"
+)
+
+type fakeImportUserRepo struct {
+	fakeUserRepo
+	existing map[string]map[string]bool
+	imported []models.ImportUser
+}
+
+func (f *fakeImportUserRepo) FindExistingValues(ctx context.Context, field string, values []string) (map[string]bool, error) {
+	found := map[string]bool{}
+	for _, v := range values {
+		if f.existing[field][v] {
+			found[v] = true
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeImportUserRepo) ImportUsers(ctx context.Context, batch []models.ImportUser) ([]error, error) {
+	f.imported = append(f.imported, batch...)
+	return make([]error, len(batch)), nil
+}
+
+type fakeRoleRepo struct {
+	repository.RoleRepository
+	roles       []models.Role
+	permissions []models.Permission
+	// admins adalah user aktif pemegang permission users:* dengan scope all
+	admins []string
+}
+
+func (f *fakeRoleRepo) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	return f.roles, nil
+}
+
+func (f *fakeRoleRepo) GetPermissionsByRoleID(ctx context.Context, roleID string) ([]models.Permission, error) {
+	return f.permissions, nil
+}
+
+func (f *fakeRoleRepo) CountUsersWithPermission(ctx context.Context, permissionName string, excludeRoleID string, excludeUserID string) (int, error) {
+	count := 0
+	for _, id := range f.admins {
+		if id != excludeUserID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeLecturerRepo struct {
+	repository.LecturerRepository
+	nips      map[string]uuid.UUID
+	lecturers map[string]models.GetLecture
+	advisees  map[string][]string
+}
+
+func (f *fakeLecturerRepo) GetLecturerIDsByNIP(ctx context.Context, nips []string) (map[string]uuid.UUID, error) {
+	ids := map[string]uuid.UUID{}
+	for _, nip := range nips {
+		if id, ok := f.nips[nip]; ok {
+			ids[nip] = id
+		}
+	}
+	return ids, nil
+}
+
+type fakeAuditRepo struct {
+	repository.AuditRepository
+	entries []models.AuditLog
+}
+
+func (f *fakeAuditRepo) Append(ctx context.Context, entry models.AuditLog) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+const importCSV = `username;email;password;full_name;role;student_id;program_study;academy_year;advisor_nip;lecturer_id;department
+budi_dosen;budi@unair.ac.id;;Budi Santoso;Dosen Wali;;;;;198001012005011002;Teknik Informatika
+andi_mhs;andi@student.unair.ac.id;password123;Andi Pratama;mahasiswa;434221001;Teknik Informatika;2022;198001012005011002;;
+citra_mhs;citra@student.unair.ac.id;password123;Citra Lestari;Mahasiswa;434221002;Teknik Informatika;2022;197001012000011001;;
+
+andi_mhs;ANDI@student.unair.ac.id;password123;Andi Lain;Mahasiswa;434221003;Teknik Informatika;2022;;;
+dedi;dedi@unair.ac.id;password123;Dedi;Dekan;;;;;;
+eko_mhs;eko@student.unair.ac.id;password123;Eko;Mahasiswa;434221099;;2022;;;
+`
+
+func postImport(t *testing.T, repos container.Repositories, query string) (int, models.ImportReport) {
+	t.Helper()
+	app, token := newFakeAppWithRepos(t, repos)
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	part, _ := mw.CreateFormFile("file", "users.csv")
+	part.Write([]byte(importCSV))
+	mw.Close()
+
+	req := httptest.NewRequest("POST", "/api/v1/users/import"+query, body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+
+	var out struct {
+		Data models.ImportReport `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out.Data
+}
+
+func TestImportUsers(t *testing.T) {
+	newRepos := func() (container.Repositories, *fakeImportUserRepo, *fakeAuditRepo) {
+		users := &fakeImportUserRepo{existing: map[string]map[string]bool{"student_id": {"434221002": true}}}
+		audit := &fakeAuditRepo{}
+		return container.Repositories{
+			User: users,
+			Role: &fakeRoleRepo{roles: []models.Role{
+				{ID: uuid.New(), Name: models.RoleMahasiswa},
+				{ID: uuid.New(), Name: models.RoleDosen},
+			}},
+			Lecturer: &fakeLecturerRepo{},
+			Audit:    audit,
+			Policy:   &fakePolicyRepo{granted: map[string]string{"users:create": models.ScopeAll}},
+		}, users, audit
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		repos, users, _ := newRepos()
+		status, report := postImport(t, repos, "")
+		if status != 200 || !report.DryRun {
+			t.Fatalf("status = %d, dry_run = %v", status, report.DryRun)
+		}
+		if len(users.imported) != 0 {
+			t.Fatalf("dry run menyimpan %d user", len(users.imported))
+		}
+
+		// Baris kosong dilewati, nomor baris tetap sesuai file
+		want := map[int][]string{
+			2: nil,
+			3: nil,
+			4: {"student_id", "advisor_nip"},
+			6: {"username", "email"},
+			7: {"role"},
+			8: {"program_study"},
+		}
+		if report.Total != len(want) || report.Valid != 2 || report.Invalid != 4 {
+			t.Errorf("total/valid/invalid = %d/%d/%d", report.Total, report.Valid, report.Invalid)
+		}
+		for _, row := range report.Rows {
+			fields := []string{}
+			for _, f := range row.Errors {
+				fields = append(fields, f.Field)
+				if f.Message == "" {
+					t.Errorf("baris %d field %s tanpa pesan", row.Line, f.Field)
+				}
+			}
+			expected, ok := want[row.Line]
+			if !ok || len(fields) != len(expected) {
+				t.Errorf("baris %d: errors = %v, want %v", row.Line, fields, expected)
+				continue
+			}
+			for i := range expected {
+				if fields[i] != expected[i] {
+					t.Errorf("baris %d: errors = %v, want %v", row.Line, fields, expected)
+				}
+			}
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		repos, users, audit := newRepos()
+		status, report := postImport(t, repos, "?dry_run=false")
+		if status != 200 || report.Created != 2 {
+			t.Fatalf("status = %d, created = %d", status, report.Created)
+		}
+		if len(users.imported) != 2 || users.imported[0].Lecture == nil {
+			t.Fatalf("imported = %+v, want dosen disimpan lebih dulu", users.imported)
+		}
+		if users.imported[1].Student.AdvisorID != users.imported[0].Lecture.ID {
+			t.Errorf("dosen wali mahasiswa tidak terhubung ke dosen di file yang sama")
+		}
+		if report.Rows[0].GeneratedPassword == "" || report.Rows[1].GeneratedPassword != "" {
+			t.Errorf("password otomatis hanya untuk baris tanpa password: %+v", report.Rows[:2])
+		}
+		if len(audit.entries) != 1 || audit.entries[0].Action != models.AuditUserImport {
+			t.Errorf("audit = %+v, want satu entri %s", audit.entries, models.AuditUserImport)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+
+	"github.com/google/uuid"
+)
+
+func (f *fakeLecturerRepo) GetLecturerByID(ctx context.Context, id string) (models.GetLecture, error) {
+	l, ok := f.lecturers[id]
+	if !ok {
+		return l, apperror.Wrap(sql.ErrNoRows, apperror.NotFound(apperror.CodeLecturerNotFound))
+	}
+	return l, nil
+}
+
+func (f *fakeLecturerRepo) UpdateLecturer(ctx context.Context, id string, req models.UpdateLecturerRequest) error {
+	for otherID, l := range f.lecturers {
+		if otherID != id && l.LecturerID == req.LecturerID {
+			return apperror.Conflict(apperror.CodeLecturerNIPTaken)
+		}
+	}
+	l := f.lecturers[id]
+	l.LecturerID, l.Department = req.LecturerID, req.Department
+	f.lecturers[id] = l
+	return nil
+}
+
+func (f *fakeLecturerRepo) ReassignAdvisees(ctx context.Context, fromID string, toID string, studentIDs []string) ([]string, error) {
+	moved, kept := []string{}, []string{}
+	for _, id := range f.advisees[fromID] {
+		if len(studentIDs) == 0 || slices.Contains(studentIDs, id) {
+			moved = append(moved, id)
+		} else {
+			kept = append(kept, id)
+		}
+	}
+	f.advisees[fromID] = kept
+	f.advisees[toID] = append(f.advisees[toID], moved...)
+	return moved, nil
+}
+
+func TestLecturerManagement(t *testing.T) {
+	leaving, successor := uuid.NewString(), uuid.NewString()
+	students := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+
+	newApp := func(t *testing.T) (*fakeLecturerRepo, func(method, path, body string) (int, map[string]interface{})) {
+		lecturers := &fakeLecturerRepo{
+			lecturers: map[string]models.GetLecture{
+				leaving:   {ID: leaving, LecturerID: "198501012010121001", Department: "Teknik Informatika"},
+				successor: {ID: successor, LecturerID: "198702022012121002", Department: "Teknik Informatika"},
+			},
+			advisees: map[string][]string{leaving: append([]string{}, students...)},
+		}
+		app, token := newFakeAppWithRepos(t, container.Repositories{
+			Lecturer: lecturers,
+			Audit:    &fakeAuditRepo{},
+			Policy: &fakePolicyRepo{granted: map[string]string{
+				"lecturers:read":   models.ScopeAll,
+				"lecturers:update": models.ScopeAll,
+			}},
+		})
+
+		return lecturers, func(method, path, body string) (int, map[string]interface{}) {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			var out map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&out)
+			return resp.StatusCode, out
+		}
+	}
+
+	errorCode := func(out map[string]interface{}) string {
+		e, _ := out["error"].(map[string]interface{})
+		code, _ := e["code"].(string)
+		return code
+	}
+
+	t.Run("detail dan update", func(t *testing.T) {
+		repo, do := newApp(t)
+		if status, _ := do("GET", "/api/v1/lecturers/"+leaving, ""); status != 200 {
+			t.Fatalf("GET status = %d", status)
+		}
+
+		status, out := do("PUT", "/api/v1/lecturers/"+leaving, `{"lecturer_id":"198702022012121002","department":"Sistem Informasi"}`)
+		if status != 409 || errorCode(out) != apperror.CodeLecturerNIPTaken {
+			t.Errorf("NIP dosen lain: status = %d, code = %s", status, errorCode(out))
+		}
+
+		status, _ = do("PUT", "/api/v1/lecturers/"+leaving, `{"lecturer_id":"198501012010121009","department":"Sistem Informasi"}`)
+		if status != 200 || repo.lecturers[leaving].Department != "Sistem Informasi" {
+			t.Errorf("update: status = %d, data = %+v", status, repo.lecturers[leaving])
+		}
+	})
+
+	t.Run("pindahkan mahasiswa bimbingan", func(t *testing.T) {
+		repo, do := newApp(t)
+		path := "/api/v1/lecturers/" + leaving + "/advisees/reassign"
+
+		if status, out := do("POST", path, `{"to_lecturer_id":"`+leaving+`"}`); errorCode(out) != apperror.CodeReassignSameLecturer {
+			t.Errorf("dosen sama: status = %d, code = %s", status, errorCode(out))
+		}
+		if status, out := do("POST", path, `{"to_lecturer_id":"`+uuid.NewString()+`"}`); status != 404 || errorCode(out) != apperror.CodeAdvisorNotFound {
+			t.Errorf("dosen tujuan tidak ada: status = %d, code = %s", status, errorCode(out))
+		}
+
+		status, out := do("POST", path, `{"to_lecturer_id":"`+successor+`","student_ids":["`+students[0]+`"]}`)
+		if data, _ := out["data"].(map[string]interface{}); status != 200 || data["moved"] != float64(1) {
+			t.Fatalf("sebagian: status = %d, body = %v", status, out)
+		}
+
+		do("POST", path, `{"to_lecturer_id":"`+successor+`"}`)
+		if len(repo.advisees[leaving]) != 0 || len(repo.advisees[successor]) != len(students) {
+			t.Errorf("advisees = %v, want seluruhnya pindah ke dosen pengganti", repo.advisees)
+		}
+	})
+}
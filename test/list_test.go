@@ -0,0 +1,77 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"uas/app/models"
+	"uas/helpers"
+	"uas/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestParseListParams(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Get("/", func(c *fiber.Ctx) error {
+		params, err := helpers.ParseListParams(c, models.StudentSortFields)
+		if err != nil {
+			return err
+		}
+		return c.JSON(params)
+	})
+
+	cursor := models.Cursor{Sort: "nim", Order: models.OrderDesc, Value: "434221001", ID: "7f1c1e8e-2f55-4a51-9d5c-0f3f6f1f2a11"}.Encode()
+
+	cases := []struct {
+		name      string
+		query     string
+		wantCode  string
+		wantParam string
+		check     func(p models.ListParams) bool
+	}{
+		{"default", "", "", "", func(p models.ListParams) bool {
+			return p.Page == 1 && p.Limit == models.DefaultListLimit && p.Sort == "full_name" && p.Order == models.OrderAsc
+		}},
+		{"sort menurun", "sort=-academy_year&q=%20andi%20", "", "", func(p models.ListParams) bool {
+			return p.Sort == "academy_year" && p.Order == models.OrderDesc && p.Search == "andi"
+		}},
+		{"limit dibatasi", "limit=500&page=3", "", "", func(p models.ListParams) bool {
+			return p.Limit == models.MaxListLimit && p.Page == 3
+		}},
+		{"cursor", "sort=nim&order=desc&cursor=" + url.QueryEscape(cursor), "", "", func(p models.ListParams) bool {
+			return p.Cursor != nil && p.Cursor.Value == "434221001"
+		}},
+		{"sort tidak dikenal", "sort=password_hash", "INVALID_QUERY", "sort", nil},
+		{"page tidak valid", "page=0", "INVALID_QUERY", "page", nil},
+		{"cursor beda urutan", "sort=nim&cursor=" + url.QueryEscape(cursor), "INVALID_QUERY", "cursor", nil},
+		{"cursor rusak", "cursor=bukan-cursor", "INVALID_QUERY", "cursor", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", "/?"+tc.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.wantCode != "" {
+				var body models.ErrorResponse
+				_ = json.NewDecoder(resp.Body).Decode(&body)
+				if resp.StatusCode != 400 || body.Error.Code != tc.wantCode || body.Error.Params["param"] != tc.wantParam {
+					t.Errorf("status %d, error %+v, want %s pada %s", resp.StatusCode, body.Error, tc.wantCode, tc.wantParam)
+				}
+				return
+			}
+
+			var params models.ListParams
+			if err := json.NewDecoder(resp.Body).Decode(&params); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != 200 || !tc.check(params) {
+				t.Errorf("status %d, params %+v", resp.StatusCode, params)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"uas/config"
+	"uas/middleware"
+	"uas/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := utils.NewLogger(config.LogConfig{Level: "info", Format: "json"}, &buf)
+
+	app := fiber.New()
+	app.Use(middleware.RequestID(logger), middleware.AccessLog())
+	app.Get("/ping/:id", func(c *fiber.Ctx) error {
+		utils.Logger(c.UserContext()).Info("dari handler")
+		return c.SendStatus(204)
+	})
+
+	req := httptest.NewRequest("GET", "/ping/1", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-123")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get(fiber.HeaderXRequestID); got != "req-123" {
+		t.Errorf("X-Request-ID = %q, want req-123", got)
+	}
+
+	// Dua baris log: dari handler dan access log, keduanya membawa request_id yang sama
+	dec := json.NewDecoder(&buf)
+	var lines []map[string]interface{}
+	for dec.More() {
+		var line map[string]interface{}
+		if err := dec.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("jumlah baris log = %d, want 2", len(lines))
+	}
+	for _, line := range lines {
+		if line["request_id"] != "req-123" {
+			t.Errorf("request_id = %v, want req-123", line["request_id"])
+		}
+	}
+	if lines[1]["route"] != "/ping/:id" || lines[1]["status"] != float64(204) {
+		t.Errorf("access log = %v", lines[1])
+	}
+
+	// Tanpa header (atau header tidak valid), ID baru dibuat
+	req = httptest.NewRequest("GET", "/ping/1", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "ada spasi")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get(fiber.HeaderXRequestID); got == "" || got == "ada spasi" {
+		t.Errorf("X-Request-ID = %q, want ID baru", got)
+	}
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/models"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	app, token := newFakeApp(t, &fakePolicyRepo{granted: map[string]string{"users:read": models.ScopeAll}})
+
+	req := httptest.NewRequest("GET", "/api/v1/users", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	// Error domain (403 dari RequirePermission) harus tercatat dengan status response, bukan 500
+	req = httptest.NewRequest("GET", "/api/v1/roles", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	// Label route memakai template route, bukan path asli
+	for _, want := range []string{
+		`uas_http_requests_total{method="GET",route="/api/v1/users",status="200"}`,
+		`uas_http_request_duration_seconds_bucket{method="GET",route="/api/v1/users",status="200"`,
+		`uas_achievements_total{event="verified"}`,
+		`uas_logins_total{reason="",result="success"}`,
+		`uas_http_requests_total{method="GET",route="/api/v1/roles",status="403"}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics tidak memuat %s", want)
+		}
+	}
+	if strings.Contains(string(body), `route="/api/v1/roles",status="500"`) {
+		t.Error("403 dari RequirePermission tercatat sebagai 500")
+	}
+}
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"uas/database/migrations"
+)
+
+// Setiap migration .up harus memiliki pasangan .down agar `migrate down` selalu bisa dijalankan
+func TestEmbeddedMigrationsArePaired(t *testing.T) {
+	sets := map[string]fs.FS{"postgres": migrations.Postgres, "mongo": migrations.Mongo}
+
+	for name, fsys := range sets {
+		var ups, downs []string
+		err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			switch {
+			case strings.Contains(path, ".up."):
+				ups = append(ups, strings.Replace(path, ".up.", ".", 1))
+			case strings.Contains(path, ".down."):
+				downs = append(downs, strings.Replace(path, ".down.", ".", 1))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if len(ups) == 0 {
+			t.Errorf("%s: tidak ada migration yang di-embed", name)
+		}
+		if strings.Join(ups, ",") != strings.Join(downs, ",") {
+			t.Errorf("%s: migration up/down tidak berpasangan\nup:   %v\ndown: %v", name, ups, downs)
+		}
+	}
+}
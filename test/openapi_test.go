@@ -0,0 +1,113 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"uas/app/models"
+	"uas/docs"
+	"uas/i18n"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+type openAPIOperation struct {
+	Permission string   `yaml:"x-permission"`
+	ErrorCodes []string `yaml:"x-error-codes"`
+}
+
+var (
+	routeParam = regexp.MustCompile(`:(\w+)`)
+	specParam  = regexp.MustCompile(`\{\w+\}`)
+	httpMethod = map[string]bool{"get": true, "post": true, "put": true, "patch": true, "delete": true}
+)
+
+// loadOpenAPI mengembalikan operasi pada dokumen dengan kunci "<method> <path>", contoh "get /api/v1/users/{id}"
+func loadOpenAPI(t *testing.T) map[string]openAPIOperation {
+	var doc struct {
+		OpenAPI string                          `yaml:"openapi"`
+		Paths   map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(docs.Spec, &doc); err != nil {
+		t.Fatalf("openapi.yaml tidak valid: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		t.Fatalf("versi openapi = %q, want 3.1.x", doc.OpenAPI)
+	}
+
+	ops := map[string]openAPIOperation{}
+	for path, items := range doc.Paths {
+		for method, node := range items {
+			if !httpMethod[method] {
+				continue
+			}
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				t.Fatalf("%s %s: %v", method, path, err)
+			}
+			ops[method+" "+path] = op
+		}
+	}
+	return ops
+}
+
+// Gagal jika ada route yang belum didokumentasikan, atau dokumen memuat route yang sudah tidak ada
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	ops := loadOpenAPI(t)
+	app, _ := newFakeApp(t, &fakePolicyRepo{})
+
+	registered := map[string]bool{}
+	for _, r := range app.GetRoutes(true) {
+		if r.Method == "HEAD" {
+			continue
+		}
+		key := strings.ToLower(r.Method) + " " + routeParam.ReplaceAllString(r.Path, "{$1}")
+		registered[key] = true
+
+		if _, ok := ops[key]; !ok {
+			t.Errorf("route %s belum ada di docs/openapi.yaml", key)
+		}
+	}
+
+	for key := range ops {
+		if !registered[key] {
+			t.Errorf("docs/openapi.yaml memuat %s yang tidak terdaftar di routes", key)
+		}
+	}
+}
+
+// x-permission harus sama dengan permission yang benar-benar dicek route, dan setiap x-error-codes harus dikenal
+func TestOpenAPIPermissionsAndErrorCodes(t *testing.T) {
+	ops := loadOpenAPI(t)
+	app, token := newFakeApp(t, &fakePolicyRepo{granted: map[string]string{}})
+
+	for key, op := range ops {
+		for _, code := range op.ErrorCodes {
+			if !i18n.Has(code) {
+				t.Errorf("%s: kode error %s tidak dikenal", key, code)
+			}
+		}
+		if op.Permission == "" {
+			continue
+		}
+
+		// Tanpa permission apa pun, route harus menolak dengan permission yang sama seperti di dokumen
+		method, path, _ := strings.Cut(key, " ")
+		req := httptest.NewRequest(strings.ToUpper(method), specParam.ReplaceAllString(path, uuid.NewString()), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body models.ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		if resp.StatusCode != 403 || body.Error.Params["permission"] != op.Permission {
+			t.Errorf("%s: status %d, permission %v, want x-permission %s",
+				key, resp.StatusCode, body.Error.Params["permission"], op.Permission)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+
+	"github.com/google/uuid"
+)
+
+type fakePersonalDataRepo struct {
+	repository.PersonalDataRepository
+	missing   map[string]bool
+	collected []string
+}
+
+func (f *fakePersonalDataRepo) CollectPersonalData(ctx context.Context, userID string) ([]models.PersonalDataSection, error) {
+	f.collected = append(f.collected, userID)
+	if f.missing[userID] {
+		return nil, apperror.Wrap(sql.ErrNoRows, apperror.NotFound(apperror.CodeUserNotFound))
+	}
+	// Entry audit: aksi user sendiri atas prestasinya, aksi admin atas akun user, dan aksi user atas prestasi orang lain
+	auditLogs := []interface{}{
+		map[string]interface{}{"id": int64(1), "actor_id": userID, "target_type": "achievement", "target_id": "ref-own",
+			"ip": "10.0.0.1", "user_agent": "browser-user", "after": json.RawMessage(`{"status":"submitted"}`)},
+		map[string]interface{}{"id": int64(2), "actor_id": "admin-id", "target_type": "user", "target_id": userID,
+			"ip": "10.0.0.9", "user_agent": "browser-admin", "before": json.RawMessage(`{"email":"andi@unair.ac.id"}`)},
+		map[string]interface{}{"id": int64(3), "actor_id": userID, "target_type": "achievement", "target_id": "ref-other",
+			"ip": "10.0.0.1", "user_agent": "browser-user", "after": json.RawMessage(`{"title":"Prestasi Budi"}`)},
+	}
+	return []models.PersonalDataSection{
+		{Name: "user", Description: "Akun pengguna", Records: []interface{}{map[string]interface{}{"id": userID, "username": "andi"}}},
+		{Name: "achievement_references", Description: "Referensi prestasi", Records: []interface{}{map[string]interface{}{"id": "ref-own"}}},
+		{Name: "achievements", Description: "Dokumen detail prestasi", Records: []interface{}{json.RawMessage(`{"title":"Juara 1"}`), json.RawMessage(`{"title":"Finalis"}`)}},
+		{Name: "audit_logs", Description: "Audit log", Records: auditLogs},
+	}, nil
+}
+
+// readZip membaca seluruh file di dalam ZIP
+func readZip(t *testing.T, body []byte) map[string][]byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("bukan file ZIP: %v", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range archive.File {
+		r, _ := f.Open()
+		files[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+	return files
+}
+
+func TestPersonalDataExport(t *testing.T) {
+	student, unknown := uuid.NewString(), uuid.NewString()
+
+	newApp := func(t *testing.T, granted map[string]string) (*fakePersonalDataRepo, func(path string) (int, []byte)) {
+		repo := &fakePersonalDataRepo{missing: map[string]bool{unknown: true}}
+		app, token := newFakeAppWithRepos(t, container.Repositories{
+			PersonalData: repo,
+			Audit:        &fakeAuditRepo{},
+			Policy:       &fakePolicyRepo{granted: granted},
+		})
+		return repo, func(path string) (int, []byte) {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			return resp.StatusCode, body
+		}
+	}
+
+	t.Run("admin mengunduh ZIP dengan manifest", func(t *testing.T) {
+		_, do := newApp(t, map[string]string{"users:personal_data": models.ScopeAll})
+
+		status, body := do("/api/v1/users/" + student + "/personal-data")
+		if status != 200 {
+			t.Fatalf("status = %d, body = %s", status, body)
+		}
+		files := readZip(t, body)
+
+		var manifest models.PersonalDataManifest
+		if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+			t.Fatalf("manifest.json: %v", err)
+		}
+		if manifest.UserID != student || manifest.RequestedBy.Self || len(manifest.Files) != 4 || len(manifest.Excluded) != len(models.PersonalDataExcluded) {
+			t.Fatalf("manifest = %+v", manifest)
+		}
+		for _, f := range manifest.Files {
+			content, ok := files[f.Name]
+			sum := sha256.Sum256(content)
+			if !ok || hex.EncodeToString(sum[:]) != f.SHA256 {
+				t.Errorf("%s: ada = %v, checksum tidak cocok", f.Name, ok)
+			}
+			if f.Name == "achievements.json" && f.Records != 2 {
+				t.Errorf("achievements.json records = %d, want 2", f.Records)
+			}
+		}
+		if audit := string(files["audit_logs.json"]); !strings.Contains(audit, "10.0.0.9") || !strings.Contains(audit, "Prestasi Budi") {
+			t.Errorf("export admin tidak boleh meredaksi audit log: %s", audit)
+		}
+	})
+
+	t.Run("izin dan user tidak dikenal", func(t *testing.T) {
+		repo, do := newApp(t, map[string]string{})
+		if status, _ := do("/api/v1/users/" + student + "/personal-data"); status != 403 || len(repo.collected) != 0 {
+			t.Errorf("tanpa izin: status = %d, collected = %v", status, repo.collected)
+		}
+
+		_, do = newApp(t, map[string]string{"users:personal_data": models.ScopeAll})
+		if status, _ := do("/api/v1/users/" + unknown + "/personal-data"); status != 404 {
+			t.Errorf("user tidak dikenal: status = %d, want 404", status)
+		}
+	})
+
+	t.Run("user mengunduh datanya sendiri tanpa izin khusus", func(t *testing.T) {
+		repo, do := newApp(t, map[string]string{})
+		status, body := do("/api/v1/auth/personal-data")
+		if status != 200 || len(repo.collected) != 1 || repo.collected[0] == student {
+			t.Fatalf("status = %d, collected = %v", status, repo.collected)
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatalf("bukan file ZIP: %v", err)
+		}
+		var manifest models.PersonalDataManifest
+		for _, f := range archive.File {
+			if f.Name == "manifest.json" {
+				r, _ := f.Open()
+				json.NewDecoder(r).Decode(&manifest)
+				r.Close()
+			}
+		}
+		if manifest.UserID != repo.collected[0] || !manifest.RequestedBy.Self {
+			t.Errorf("manifest = %+v, want data user yang login", manifest)
+		}
+	})
+
+	t.Run("export mandiri meredaksi data pihak lain pada audit log", func(t *testing.T) {
+		_, do := newApp(t, map[string]string{})
+		status, body := do("/api/v1/auth/personal-data")
+		if status != 200 {
+			t.Fatalf("status = %d, body = %s", status, body)
+		}
+
+		var entries []map[string]interface{}
+		if err := json.Unmarshal(readZip(t, body)["audit_logs.json"], &entries); err != nil || len(entries) != 3 {
+			t.Fatalf("audit_logs.json: %v, entries = %v", err, entries)
+		}
+		own, byAdmin, otherTarget := entries[0], entries[1], entries[2]
+		if own["ip"] != "10.0.0.1" || own["after"] == nil {
+			t.Errorf("entry milik user sendiri ikut diredaksi: %v", own)
+		}
+		if byAdmin["ip"] != nil || byAdmin["user_agent"] != nil || byAdmin["before"] == nil {
+			t.Errorf("IP/user agent admin harus diredaksi, snapshot akun user tetap ada: %v", byAdmin)
+		}
+		if otherTarget["after"] != nil || otherTarget["ip"] != "10.0.0.1" {
+			t.Errorf("snapshot data pihak lain harus diredaksi: %v", otherTarget)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"uas/database"
+)
+
+func TestRetrySucceedsAfterFailures(t *testing.T) {
+	calls := 0
+	err := database.Retry(context.Background(), "dummy", 3, 0, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("belum siap")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	cause := errors.New("tidak tersedia")
+	calls := 0
+	err := database.Retry(context.Background(), "dummy", 2, 0, func(ctx context.Context) error {
+		calls++
+		return cause
+	})
+
+	if !errors.Is(err, cause) {
+		t.Errorf("err = %v, want wrapping %v", err, cause)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
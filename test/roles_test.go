@@ -0,0 +1,296 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+
+	"github.com/google/uuid"
+)
+
+// fakeRoleStore menyimpan role, grant beserta scope-nya dan jumlah user per role di memori
+type fakeRoleStore struct {
+	repository.RoleRepository
+	roles       map[string]models.Role
+	permissions map[string]models.Permission
+	grants      map[string]map[string]string
+	users       map[string]int
+}
+
+func (f *fakeRoleStore) GetRoleByID(ctx context.Context, id string) (models.Role, error) {
+	role, ok := f.roles[id]
+	if !ok {
+		return models.Role{}, apperror.NotFound(apperror.CodeRoleNotFound)
+	}
+	return role, nil
+}
+
+func (f *fakeRoleStore) CreateRole(ctx context.Context, role models.Role) error {
+	f.roles[role.ID.String()] = role
+	return nil
+}
+
+func (f *fakeRoleStore) UpdateRole(ctx context.Context, id string, name string, description string) error {
+	role := f.roles[id]
+	role.Name, role.Description = name, description
+	f.roles[id] = role
+	return nil
+}
+
+func (f *fakeRoleStore) DeleteRole(ctx context.Context, id string) error {
+	delete(f.roles, id)
+	return nil
+}
+
+func (f *fakeRoleStore) CountUsersByRole(ctx context.Context, roleID string) (int, error) {
+	return f.users[roleID], nil
+}
+
+func (f *fakeRoleStore) GetPermissionByID(ctx context.Context, id string) (models.Permission, error) {
+	p, ok := f.permissions[id]
+	if !ok {
+		return models.Permission{}, apperror.NotFound(apperror.CodePermissionNotFound)
+	}
+	return p, nil
+}
+
+func (f *fakeRoleStore) GetPermissionsByRoleID(ctx context.Context, roleID string) ([]models.Permission, error) {
+	var perms []models.Permission
+	for id, scope := range f.grants[roleID] {
+		p := f.permissions[id]
+		p.Scope = scope
+		perms = append(perms, p)
+	}
+	return perms, nil
+}
+
+func (f *fakeRoleStore) GrantPermission(ctx context.Context, roleID string, permissionID string, scope string) error {
+	if f.grants[roleID] == nil {
+		f.grants[roleID] = map[string]string{}
+	}
+	f.grants[roleID][permissionID] = scope
+	return nil
+}
+
+func (f *fakeRoleStore) RevokePermission(ctx context.Context, roleID string, permissionID string) error {
+	if _, ok := f.grants[roleID][permissionID]; !ok {
+		return apperror.NotFound(apperror.CodeRolePermissionNotFound)
+	}
+	delete(f.grants[roleID], permissionID)
+	return nil
+}
+
+func (f *fakeRoleStore) CountUsersWithPermission(ctx context.Context, permissionName string, excludeRoleID string, excludeUserID string) (int, error) {
+	count := 0
+	for roleID, grants := range f.grants {
+		if roleID == excludeRoleID {
+			continue
+		}
+		for id, scope := range grants {
+			if f.permissions[id].Name == permissionName && scope == models.ScopeAll {
+				count += f.users[roleID]
+			}
+		}
+	}
+	return count, nil
+}
+
+type roleFixture struct {
+	store                  *fakeRoleStore
+	adminRole, customRole  string
+	rolesUpdate, usersRead string
+	do                     func(method, path, body string) (int, string)
+}
+
+// newRoleFixture menyiapkan role Admin (satu user, roles:update scope all) dan role kustom kosong
+func newRoleFixture(t *testing.T) roleFixture {
+	adminRole, customRole := uuid.New(), uuid.New()
+	rolesUpdate, usersRead := uuid.New(), uuid.New()
+
+	store := &fakeRoleStore{
+		roles: map[string]models.Role{
+			adminRole.String():  {ID: adminRole, Name: models.RoleAdmin},
+			customRole.String(): {ID: customRole, Name: "Operator"},
+		},
+		permissions: map[string]models.Permission{
+			rolesUpdate.String(): {ID: rolesUpdate, Name: "roles:update", Resource: "roles", Action: "update"},
+			usersRead.String():   {ID: usersRead, Name: "users:read", Resource: "users", Action: "read"},
+		},
+		grants: map[string]map[string]string{
+			adminRole.String(): {rolesUpdate.String(): models.ScopeAll},
+		},
+		users: map[string]int{adminRole.String(): 1},
+	}
+
+	app, token := newFakeAppWithRepos(t, container.Repositories{
+		Role:  store,
+		Audit: &fakeAuditRepo{},
+		Policy: &fakePolicyRepo{granted: map[string]string{
+			"roles:create": models.ScopeAll,
+			"roles:update": models.ScopeAll,
+			"roles:delete": models.ScopeAll,
+		}},
+	})
+
+	do := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out struct {
+			Error struct{ Code string } `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out.Error.Code
+	}
+
+	return roleFixture{
+		store:       store,
+		adminRole:   adminRole.String(),
+		customRole:  customRole.String(),
+		rolesUpdate: rolesUpdate.String(),
+		usersRead:   usersRead.String(),
+		do:          do,
+	}
+}
+
+func TestRoleCRUD(t *testing.T) {
+	f := newRoleFixture(t)
+
+	if status, _ := f.do("POST", "/api/v1/roles", `{"name":"  Auditor  ","description":"Pemeriksa"}`); status != 201 {
+		t.Fatalf("create role: status = %d", status)
+	}
+	var created models.Role
+	for _, role := range f.store.roles {
+		if role.Name == "Auditor" {
+			created = role
+		}
+	}
+	if created.ID == uuid.Nil {
+		t.Fatal("role baru tidak tersimpan dengan nama yang sudah di-trim")
+	}
+
+	if status, code := f.do("POST", "/api/v1/roles", `{"name":"   "}`); status != 400 || code != apperror.CodeValidation {
+		t.Errorf("nama kosong: status = %d, code = %s", status, code)
+	}
+
+	if status, _ := f.do("PUT", "/api/v1/roles/"+created.ID.String(), `{"name":"Auditor Internal"}`); status != 200 {
+		t.Errorf("update role: status = %d", status)
+	}
+	if got := f.store.roles[created.ID.String()].Name; got != "Auditor Internal" {
+		t.Errorf("nama role = %q setelah update", got)
+	}
+
+	// Role bawaan hanya boleh diubah deskripsinya
+	if status, code := f.do("PUT", "/api/v1/roles/"+f.adminRole, `{"name":"Superuser"}`); status != 400 || code != apperror.CodeSystemRoleProtected {
+		t.Errorf("rename role bawaan: status = %d, code = %s", status, code)
+	}
+	if status, _ := f.do("PUT", "/api/v1/roles/"+f.adminRole, `{"name":"Admin","description":"Administrator"}`); status != 200 {
+		t.Errorf("update deskripsi role bawaan: status = %d", status)
+	}
+
+	if status, code := f.do("PUT", "/api/v1/roles/"+uuid.NewString(), `{"name":"X"}`); status != 404 || code != apperror.CodeRoleNotFound {
+		t.Errorf("update role tidak ada: status = %d, code = %s", status, code)
+	}
+
+	if status, code := f.do("DELETE", "/api/v1/roles/"+f.adminRole, ""); status != 400 || code != apperror.CodeSystemRoleProtected {
+		t.Errorf("hapus role bawaan: status = %d, code = %s", status, code)
+	}
+
+	f.store.users[f.customRole] = 2
+	if status, code := f.do("DELETE", "/api/v1/roles/"+f.customRole, ""); status != 409 || code != apperror.CodeRoleInUse {
+		t.Errorf("hapus role yang dipakai: status = %d, code = %s", status, code)
+	}
+
+	if status, _ := f.do("DELETE", "/api/v1/roles/"+created.ID.String(), ""); status != 200 {
+		t.Errorf("hapus role: status = %d", status)
+	}
+	if _, ok := f.store.roles[created.ID.String()]; ok {
+		t.Error("role masih tersimpan setelah dihapus")
+	}
+}
+
+func TestRoleGrantAndRevokePermission(t *testing.T) {
+	f := newRoleFixture(t)
+	path := "/api/v1/roles/" + f.customRole + "/permissions"
+
+	cases := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"tanpa scope", `{"permission_id":"` + f.usersRead + `"}`, 400, apperror.CodeValidation},
+		{"scope tidak dikenal", `{"permission_id":"` + f.usersRead + `","scope":"everything"}`, 400, apperror.CodeValidation},
+		{"permission tidak ada", `{"permission_id":"` + uuid.NewString() + `","scope":"all"}`, 404, apperror.CodePermissionNotFound},
+		{"scope own", `{"permission_id":"` + f.usersRead + `","scope":"own"}`, 200, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status, code := f.do("POST", path, tc.body); status != tc.status || code != tc.code {
+				t.Errorf("status = %d, code = %s, want %d %s", status, code, tc.status, tc.code)
+			}
+		})
+	}
+	if got := f.store.grants[f.customRole][f.usersRead]; got != models.ScopeOwn {
+		t.Errorf("scope grant = %q, want %q", got, models.ScopeOwn)
+	}
+
+	if status, _ := f.do("DELETE", path+"/"+f.usersRead, ""); status != 200 {
+		t.Errorf("revoke: status = %d", status)
+	}
+	if status, code := f.do("DELETE", path+"/"+f.usersRead, ""); status != 404 || code != apperror.CodeRolePermissionNotFound {
+		t.Errorf("revoke ulang: status = %d, code = %s", status, code)
+	}
+
+	// Role Admin satu-satunya pemegang roles:update dengan scope all
+	adminPath := "/api/v1/roles/" + f.adminRole + "/permissions"
+	if status, code := f.do("DELETE", adminPath+"/"+f.rolesUpdate, ""); status != 409 || code != apperror.CodeLastAdminPermission {
+		t.Errorf("revoke admin terakhir: status = %d, code = %s", status, code)
+	}
+	if _, ok := f.store.grants[f.adminRole][f.rolesUpdate]; !ok {
+		t.Error("grant admin terakhir tidak boleh dicabut")
+	}
+}
+
+func TestRoleGrantRefusesLastAdminScopeDowngrade(t *testing.T) {
+	f := newRoleFixture(t)
+	adminPath := "/api/v1/roles/" + f.adminRole + "/permissions"
+	downgrade := `{"permission_id":"` + f.rolesUpdate + `","scope":"own"}`
+
+	if status, code := f.do("POST", adminPath, downgrade); status != 409 || code != apperror.CodeLastAdminPermission {
+		t.Errorf("turunkan scope admin terakhir: status = %d, code = %s", status, code)
+	}
+	if got := f.store.grants[f.adminRole][f.rolesUpdate]; got != models.ScopeAll {
+		t.Errorf("scope admin terakhir = %q, want %q", got, models.ScopeAll)
+	}
+
+	// Grant ulang dengan scope all tetap diterima
+	if status, _ := f.do("POST", adminPath, `{"permission_id":"`+f.rolesUpdate+`","scope":"all"}`); status != 200 {
+		t.Errorf("grant ulang scope all: status = %d", status)
+	}
+
+	// Scope own milik role lain tidak dihitung sebagai admin efektif
+	f.store.grants[f.customRole] = map[string]string{f.rolesUpdate: models.ScopeOwn}
+	f.store.users[f.customRole] = 1
+	if status, code := f.do("POST", adminPath, downgrade); status != 409 || code != apperror.CodeLastAdminPermission {
+		t.Errorf("admin lain hanya scope own: status = %d, code = %s", status, code)
+	}
+
+	f.store.grants[f.customRole][f.rolesUpdate] = models.ScopeAll
+	if status, _ := f.do("POST", adminPath, downgrade); status != 200 {
+		t.Errorf("turunkan scope dengan admin lain: status = %d", status)
+	}
+	if got := f.store.grants[f.adminRole][f.rolesUpdate]; got != models.ScopeOwn {
+		t.Errorf("scope setelah downgrade = %q, want %q", got, models.ScopeOwn)
+	}
+}
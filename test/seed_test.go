@@ -0,0 +1,68 @@
+package test
+
+import (
+	"testing"
+	"uas/database/seeder"
+	"uas/helpers"
+)
+
+// Memastikan file seed konsisten sebelum dijalankan ke database
+func TestSeedFileIsConsistent(t *testing.T) {
+	file, err := seeder.Load("../" + seeder.DefaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	permissions := map[string]bool{}
+	for _, p := range file.Permissions {
+		permissions[p.Name] = true
+	}
+
+	roles := map[string]bool{}
+	for _, r := range file.Roles {
+		roles[r.Name] = true
+		for perm, scope := range r.Permissions {
+			if !permissions[perm] {
+				t.Errorf("role %s: permission %s tidak terdaftar", r.Name, perm)
+			}
+			if !helpers.IsValidScope(scope) {
+				t.Errorf("role %s: scope %q untuk %s tidak valid", r.Name, scope, perm)
+			}
+		}
+	}
+
+	for _, name := range []string{"prod", "demo"} {
+		profile, err := file.Profile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nips, nims, usernames := map[string]bool{}, map[string]bool{}, map[string]bool{}
+		for _, u := range profile.Users {
+			usernames[u.Username] = true
+			if !roles[u.Role] {
+				t.Errorf("%s: role %q milik %s tidak terdaftar", name, u.Role, u.Username)
+			}
+			if u.Lecturer != nil {
+				nips[u.Lecturer.NIP] = true
+			}
+		}
+		for _, u := range profile.Users {
+			if u.Student == nil {
+				continue
+			}
+			nims[u.Student.NIM] = true
+			if u.Student.AdvisorNIP != "" && !nips[u.Student.AdvisorNIP] {
+				t.Errorf("%s: dosen wali %s milik %s tidak ada di seed", name, u.Student.AdvisorNIP, u.Username)
+			}
+		}
+		for _, a := range profile.Achievements {
+			if !nims[a.NIM] {
+				t.Errorf("%s: NIM %s untuk prestasi %q tidak ada di seed", name, a.NIM, a.Title)
+			}
+			if a.VerifiedBy != "" && !usernames[a.VerifiedBy] {
+				t.Errorf("%s: verifikator %s untuk prestasi %q tidak ada di seed", name, a.VerifiedBy, a.Title)
+			}
+		}
+	}
+}
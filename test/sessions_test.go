@@ -0,0 +1,273 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/utils"
+
+	"github.com/google/uuid"
+)
+
+// fakeAuthSessionRepo menyimpan sesi di memori beserta status pencabutannya
+type fakeAuthSessionRepo struct {
+	repository.SessionRepository
+	sessions []models.Session
+}
+
+func (f *fakeAuthSessionRepo) CreateSession(ctx context.Context, session models.Session) error {
+	f.sessions = append(f.sessions, session)
+	return nil
+}
+
+func (f *fakeAuthSessionRepo) GetActiveSession(ctx context.Context, id string) (models.Session, error) {
+	for _, s := range f.sessions {
+		if s.ID.String() == id && s.RevokedAt == nil {
+			return s, nil
+		}
+	}
+	return models.Session{}, apperror.NotFound(apperror.CodeSessionNotFound)
+}
+
+func (f *fakeAuthSessionRepo) GetActiveSessionsByUserID(ctx context.Context, userID string) ([]models.Session, error) {
+	var active []models.Session
+	for _, s := range f.sessions {
+		if s.UserID.String() == userID && s.RevokedAt == nil {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeAuthSessionRepo) TouchSession(ctx context.Context, id string) error {
+	return nil
+}
+
+func (f *fakeAuthSessionRepo) RevokeSession(ctx context.Context, id string, userID string, revokedBy string) error {
+	for i, s := range f.sessions {
+		if s.ID.String() == id && s.UserID.String() == userID && s.RevokedAt == nil {
+			now := time.Now()
+			f.sessions[i].RevokedAt = &now
+			return nil
+		}
+	}
+	return apperror.NotFound(apperror.CodeSessionNotFound)
+}
+
+func (f *fakeAuthSessionRepo) RevokeSessionsByUserID(ctx context.Context, userID string, revokedBy string) ([]string, error) {
+	var ids []string
+	for i, s := range f.sessions {
+		if s.UserID.String() == userID && s.RevokedAt == nil {
+			now := time.Now()
+			f.sessions[i].RevokedAt = &now
+			ids = append(ids, s.ID.String())
+		}
+	}
+	return ids, nil
+}
+
+// fakeLoginUserRepo mencari user untuk login berdasarkan username
+type fakeLoginUserRepo struct {
+	fakeUpdateUserRepo
+}
+
+func (f *fakeLoginUserRepo) Login(ctx context.Context, loginInput string) (models.User, error) {
+	for _, u := range f.users {
+		if u.Username == loginInput {
+			return u, nil
+		}
+	}
+	return models.User{}, apperror.NotFound(apperror.CodeUserNotFound)
+}
+
+type sessionFixture struct {
+	users    *fakeLoginUserRepo
+	sessions *fakeAuthSessionRepo
+	user     models.User
+	// access dan refresh berisi token per sesi milik user
+	access, refresh map[string]string
+	adminToken      string
+	do              func(method, path, token, body string) (int, map[string]interface{})
+}
+
+// newSessionFixture menyiapkan user Mahasiswa dengan dua sesi aktif dan satu sesi milik user lain
+func newSessionFixture(t *testing.T) sessionFixture {
+	user := models.User{ID: uuid.New(), Username: "andi", RoleName: models.RoleMahasiswa, IsActive: true}
+	other := uuid.New()
+
+	sessions := &fakeAuthSessionRepo{}
+	for _, owner := range []uuid.UUID{user.ID, user.ID, other} {
+		sessions.sessions = append(sessions.sessions, models.Session{
+			ID:        uuid.New(),
+			UserID:    owner,
+			CreatedAt: time.Now(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+	}
+
+	hash, err := utils.HashPassword("password123")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	user.PasswordHash = hash
+
+	users := &fakeLoginUserRepo{fakeUpdateUserRepo{users: map[uuid.UUID]models.User{user.ID: user}}}
+	app, adminToken := newFakeAppWithRepos(t, container.Repositories{
+		User:    users,
+		Session: sessions,
+		Audit:   &fakeAuditRepo{},
+		Policy:  &fakePolicyRepo{granted: map[string]string{"users:update": models.ScopeAll}},
+	})
+
+	access, refresh := map[string]string{}, map[string]string{}
+	for _, s := range sessions.sessions[:2] {
+		sid := s.ID.String()
+		if access[sid], err = utils.GenerateToken(user, sid); err != nil {
+			t.Fatalf("generate token: %v", err)
+		}
+		if refresh[sid], err = utils.GenerateRefreshToken(user, sid); err != nil {
+			t.Fatalf("generate refresh token: %v", err)
+		}
+	}
+
+	do := func(method, path, token, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	return sessionFixture{
+		users:      users,
+		sessions:   sessions,
+		user:       user,
+		access:     access,
+		refresh:    refresh,
+		adminToken: adminToken,
+		do:         do,
+	}
+}
+
+func (f sessionFixture) refreshWith(sid string) (int, string) {
+	status, out := f.do("POST", "/api/v1/auth/refresh", "", `{"refreshToken":"`+f.refresh[sid]+`"}`)
+	e, _ := out["error"].(map[string]interface{})
+	code, _ := e["code"].(string)
+	return status, code
+}
+
+func TestSessionListingAndSelfRevoke(t *testing.T) {
+	f := newSessionFixture(t)
+	current, second, foreign := f.sessions.sessions[0].ID.String(), f.sessions.sessions[1].ID.String(), f.sessions.sessions[2].ID.String()
+
+	status, out := f.do("GET", "/api/v1/auth/sessions", f.access[current], "")
+	list, _ := out["data"].([]interface{})
+	if status != 200 || len(list) != 2 {
+		t.Fatalf("daftar sesi: status = %d, data = %v", status, out["data"])
+	}
+	for _, item := range list {
+		s := item.(map[string]interface{})
+		if want := s["id"] == current; s["current"] != want {
+			t.Errorf("sesi %v: current = %v, want %v", s["id"], s["current"], want)
+		}
+	}
+
+	// Sesi milik user lain tidak dapat dicabut
+	if status, _ := f.do("DELETE", "/api/v1/auth/sessions/"+foreign, f.access[current], ""); status != 404 {
+		t.Errorf("cabut sesi user lain: status = %d, want 404", status)
+	}
+
+	if status, _ := f.do("DELETE", "/api/v1/auth/sessions/"+second, f.access[current], ""); status != 200 {
+		t.Fatalf("cabut sesi sendiri: status = %d", status)
+	}
+	if status, out := f.do("GET", "/api/v1/auth/sessions", f.access[current], ""); status != 200 || len(out["data"].([]interface{})) != 1 {
+		t.Errorf("daftar sesi setelah dicabut: status = %d, data = %v", status, out["data"])
+	}
+
+	if status, code := f.refreshWith(second); status != 401 || code != apperror.CodeSessionRevoked {
+		t.Errorf("refresh sesi yang dicabut: status = %d, code = %s", status, code)
+	}
+	if status, _ := f.do("GET", "/api/v1/auth/profile", f.access[second], ""); status != 401 {
+		t.Errorf("access token sesi yang dicabut: status = %d, want 401", status)
+	}
+	if status, _ := f.refreshWith(current); status != 200 {
+		t.Errorf("refresh sesi aktif: status = %d", status)
+	}
+}
+
+// Login dan refresh memakai envelope {success, message, data} seperti endpoint lain
+func TestLoginAndRefreshResponseEnvelope(t *testing.T) {
+	f := newSessionFixture(t)
+
+	status, out := f.do("POST", "/api/v1/auth/login", "", `{"username":"andi","password":"password123"}`)
+	data, _ := out["data"].(map[string]interface{})
+	if status != 200 || out["success"] != true || out["message"] == "" || data["token"] == nil || data["refreshToken"] == nil {
+		t.Fatalf("login: status = %d, body = %v", status, out)
+	}
+	if _, ok := out["status"]; ok {
+		t.Error("response login masih berisi field status")
+	}
+
+	refreshToken, _ := data["refreshToken"].(string)
+	status, out = f.do("POST", "/api/v1/auth/refresh", "", `{"refreshToken":"`+refreshToken+`"}`)
+	data, _ = out["data"].(map[string]interface{})
+	if status != 200 || out["success"] != true || out["message"] == "" || data["token"] == nil {
+		t.Errorf("refresh: status = %d, body = %v", status, out)
+	}
+}
+
+func TestForceLogoutRevokesAllSessions(t *testing.T) {
+	f := newSessionFixture(t)
+
+	status, out := f.do("POST", "/api/v1/users/"+f.user.ID.String()+"/logout", f.adminToken, "")
+	data, _ := out["data"].(map[string]interface{})
+	if status != 200 || data["revoked_sessions"] != float64(2) {
+		t.Fatalf("force logout: status = %d, data = %v", status, data)
+	}
+	if f.sessions.sessions[2].RevokedAt != nil {
+		t.Error("sesi user lain ikut dicabut")
+	}
+
+	for sid := range f.refresh {
+		if status, code := f.refreshWith(sid); status != 401 || code != apperror.CodeSessionRevoked {
+			t.Errorf("refresh %s setelah force logout: status = %d, code = %s", sid, status, code)
+		}
+		if status, _ := f.do("GET", "/api/v1/auth/profile", f.access[sid], ""); status != 401 {
+			t.Errorf("access token %s setelah force logout: status = %d, want 401", sid, status)
+		}
+	}
+}
+
+func TestRefreshRejectsInactiveOrDeletedUser(t *testing.T) {
+	f := newSessionFixture(t)
+	sid := f.sessions.sessions[0].ID.String()
+
+	inactive := f.user
+	inactive.IsActive = false
+	f.users.users[f.user.ID] = inactive
+	if status, code := f.refreshWith(sid); status != 403 || code != apperror.CodeAccountInactive {
+		t.Errorf("refresh user nonaktif: status = %d, code = %s", status, code)
+	}
+
+	deleted := f.user
+	now := time.Now()
+	deleted.DeletedAt = &now
+	f.users.users[f.user.ID] = deleted
+	if status, code := f.refreshWith(sid); status != 403 || code != apperror.CodeAccountInactive {
+		t.Errorf("refresh user terhapus: status = %d, code = %s", status, code)
+	}
+}
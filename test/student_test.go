@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+
+	"github.com/google/uuid"
+)
+
+type fakeStudentRepo struct {
+	repository.StudentRepository
+	students map[string]models.GetStudent
+	history  map[string][]models.StudentStatusHistory
+}
+
+func (f *fakeStudentRepo) GetStudentByID(ctx context.Context, id string) (models.GetStudent, error) {
+	s, ok := f.students[id]
+	if !ok {
+		return s, apperror.Wrap(sql.ErrNoRows, apperror.NotFound(apperror.CodeStudentNotFound))
+	}
+	return s, nil
+}
+
+func (f *fakeStudentRepo) UpdateStudent(ctx context.Context, id string, req models.UpdateStudentRequest) error {
+	for otherID, s := range f.students {
+		if otherID != id && s.NIM == req.StudentID {
+			return apperror.Conflict(apperror.CodeStudentNIMTaken)
+		}
+	}
+	s := f.students[id]
+	s.NIM, s.ProgramStudy, s.AcademyYear = req.StudentID, req.ProgramStudy, req.AcademicYear
+	f.students[id] = s
+	return nil
+}
+
+func (f *fakeStudentRepo) UpdateStudentStatus(ctx context.Context, id string, status string, reason string, changedBy string) (string, error) {
+	s, err := f.GetStudentByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !models.CanTransitionStudentStatus(s.Status, status) {
+		return s.Status, apperror.Conflict(apperror.CodeStudentStatusTransition).With("from", s.Status).With("to", status)
+	}
+	previous := s.Status
+	s.Status = status
+	f.students[id] = s
+	f.history[id] = append([]models.StudentStatusHistory{{StudentID: id, FromStatus: previous, ToStatus: status, Reason: reason, ChangedBy: changedBy}}, f.history[id]...)
+	return previous, nil
+}
+
+func (f *fakeStudentRepo) GetStudentStatusHistory(ctx context.Context, id string) ([]models.StudentStatusHistory, error) {
+	return append([]models.StudentStatusHistory{}, f.history[id]...), nil
+}
+
+type fakeAchievementRepo struct {
+	repository.AchievementRepository
+	refs     map[string]models.AchievementReference
+	students *fakeStudentRepo
+}
+
+func (f *fakeAchievementRepo) GetAchievementByID(ctx context.Context, id string) (models.AchievementReference, error) {
+	ref, ok := f.refs[id]
+	if !ok {
+		return ref, apperror.Wrap(sql.ErrNoRows, apperror.NotFound(apperror.CodeAchievementNotFound))
+	}
+	return ref, nil
+}
+
+func (f *fakeAchievementRepo) GetStudentStatus(ctx context.Context, studentID string) (string, error) {
+	s, err := f.students.GetStudentByID(ctx, studentID)
+	return s.Status, err
+}
+
+func (f *fakeAchievementRepo) SubmitAchievement(ctx context.Context, id string) error {
+	ref := f.refs[id]
+	ref.Status = "submitted"
+	f.refs[id] = ref
+	return nil
+}
+
+func TestStudentProfileAndStatus(t *testing.T) {
+	alumni, current := uuid.NewString(), uuid.NewString()
+
+	newApp := func(t *testing.T) (*fakeStudentRepo, *fakeAchievementRepo, func(method, path, body string) (int, map[string]interface{})) {
+		students := &fakeStudentRepo{
+			students: map[string]models.GetStudent{
+				alumni:  {ID: alumni, NIM: "434221001", ProgramStudy: "Teknik Informatika", Status: models.StudentActive},
+				current: {ID: current, NIM: "434221002", ProgramStudy: "Teknik Informatika", Status: models.StudentActive},
+			},
+			history: map[string][]models.StudentStatusHistory{},
+		}
+		achievements := &fakeAchievementRepo{
+			refs: map[string]models.AchievementReference{
+				"a1": {ID: "a1", StudentID: alumni, Status: "draft"},
+				"a2": {ID: "a2", StudentID: current, Status: "draft"},
+			},
+			students: students,
+		}
+		app, token := newFakeAppWithRepos(t, container.Repositories{
+			Student:     students,
+			Achievement: achievements,
+			Audit:       &fakeAuditRepo{},
+			Policy: &fakePolicyRepo{granted: map[string]string{
+				"students:read":       models.ScopeAll,
+				"students:update":     models.ScopeAll,
+				"achievements:update": models.ScopeAll,
+			}},
+		})
+
+		return students, achievements, func(method, path, body string) (int, map[string]interface{}) {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			var out map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&out)
+			return resp.StatusCode, out
+		}
+	}
+
+	errorCode := func(out map[string]interface{}) string {
+		e, _ := out["error"].(map[string]interface{})
+		code, _ := e["code"].(string)
+		return code
+	}
+
+	t.Run("koreksi data akademik", func(t *testing.T) {
+		repo, _, do := newApp(t)
+
+		status, out := do("PUT", "/api/v1/students/"+alumni, `{"student_id":"434221002","program_study":"Sistem Informasi"}`)
+		if status != 409 || errorCode(out) != apperror.CodeStudentNIMTaken {
+			t.Errorf("NIM mahasiswa lain: status = %d, code = %s", status, errorCode(out))
+		}
+
+		status, _ = do("PUT", "/api/v1/students/"+alumni, `{"student_id":"434221009","program_study":"Sistem Informasi","academy_year":"2021"}`)
+		if s := repo.students[alumni]; status != 200 || s.NIM != "434221009" || s.AcademyYear != "2021" {
+			t.Errorf("update: status = %d, data = %+v", status, s)
+		}
+	})
+
+	t.Run("transisi status dan riwayat", func(t *testing.T) {
+		repo, _, do := newApp(t)
+		path := "/api/v1/students/" + alumni + "/status"
+
+		if status, _ := do("PUT", path, `{"status":"alumni","reason":"x"}`); status != 400 {
+			t.Errorf("status tidak dikenal: status = %d, want 400", status)
+		}
+		if status, _ := do("PUT", path, `{"status":"graduated","reason":"Yudisium Juli 2026"}`); status != 200 {
+			t.Fatalf("active -> graduated: status = %d", status)
+		}
+
+		status, out := do("PUT", path, `{"status":"active","reason":"salah input"}`)
+		if status != 409 || errorCode(out) != apperror.CodeStudentStatusTransition {
+			t.Errorf("graduated -> active: status = %d, code = %s", status, errorCode(out))
+		}
+
+		_, out = do("GET", "/api/v1/students/"+alumni+"/status-history", "")
+		if history, _ := out["data"].([]interface{}); len(history) != 1 || len(repo.history[alumni]) != 1 {
+			t.Errorf("riwayat = %v, want satu perubahan", out["data"])
+		}
+	})
+
+	t.Run("hanya mahasiswa aktif yang dapat mengajukan prestasi", func(t *testing.T) {
+		_, achievements, do := newApp(t)
+		do("PUT", "/api/v1/students/"+alumni+"/status", `{"status":"graduated","reason":"Yudisium Juli 2026"}`)
+
+		status, out := do("POST", "/api/v1/achievements/a1/submit", "")
+		if status != 409 || errorCode(out) != apperror.CodeStudentNotActive || achievements.refs["a1"].Status != "draft" {
+			t.Errorf("mahasiswa lulus: status = %d, code = %s", status, errorCode(out))
+		}
+
+		if status, _ := do("POST", "/api/v1/achievements/a2/submit", ""); status != 200 || achievements.refs["a2"].Status != "submitted" {
+			t.Errorf("mahasiswa aktif: status = %d", status)
+		}
+	})
+}
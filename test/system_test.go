@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/config"
+	"uas/middleware"
+	"uas/routes"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeSystemRepo struct {
+	repository.SystemRepository
+	mongoErr  error
+	migration models.MigrationStatus
+}
+
+func (f *fakeSystemRepo) PingPostgres(ctx context.Context) error { return nil }
+func (f *fakeSystemRepo) PingMongo(ctx context.Context) error    { return f.mongoErr }
+func (f *fakeSystemRepo) GetMigrationVersion(ctx context.Context) (models.MigrationStatus, error) {
+	return f.migration, nil
+}
+
+func TestReadyz(t *testing.T) {
+	cases := []struct {
+		name string
+		repo *fakeSystemRepo
+		want int
+	}{
+		{"semua siap", &fakeSystemRepo{migration: models.MigrationStatus{Version: 11}}, 200},
+		{"mongo mati", &fakeSystemRepo{mongoErr: errors.New("connection refused"), migration: models.MigrationStatus{Version: 11}}, 503},
+		{"migration dirty", &fakeSystemRepo{migration: models.MigrationStatus{Version: 11, Dirty: true}}, 503},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+			routes.SetupRoutes(app, container.Build(&config.Config{}, container.Repositories{System: tc.repo, Policy: &fakePolicyRepo{}}))
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if resp.StatusCode != tc.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.want)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			var report models.ReadinessSummary
+			if err := json.Unmarshal(body, &report); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if report.Checks["postgres"] == "" || report.Checks["mongo"] == "" || report.Checks["migration"] == "" {
+				t.Errorf("checks = %v, want postgres, mongo dan migration", report.Checks)
+			}
+			if report.MigrationVersion != tc.repo.migration.Version || report.Dirty != tc.repo.migration.Dirty {
+				t.Errorf("migration = %d dirty=%t, want %d dirty=%t", report.MigrationVersion, report.Dirty, tc.repo.migration.Version, tc.repo.migration.Dirty)
+			}
+			// Endpoint publik tidak boleh membocorkan pesan error driver
+			if strings.Contains(string(body), "connection refused") {
+				t.Errorf("body berisi pesan error driver: %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"uas/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddlewareContinuesTraceparent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	app.Get("/achievements/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(500)
+	})
+
+	req := httptest.NewRequest("GET", "/achievements/123", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("jumlah span = %d, want 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "GET /achievements/:id" {
+		t.Errorf("nama span = %q", span.Name())
+	}
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s, want trace dari traceparent", got)
+	}
+	if span.Status().Code.String() != "Error" {
+		t.Errorf("status span = %v, want Error untuk respons 500", span.Status().Code)
+	}
+}
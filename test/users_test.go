@@ -0,0 +1,269 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uas/app/apperror"
+	"uas/app/container"
+	"uas/app/models"
+	"uas/app/repository"
+	"uas/utils"
+
+	"github.com/google/uuid"
+)
+
+type fakeUpdateUserRepo struct {
+	repository.UserRepository
+	users map[uuid.UUID]models.User
+}
+
+func (f *fakeUpdateUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
+	return f.users[id], nil
+}
+
+func (f *fakeUpdateUserRepo) UpdateUser(ctx context.Context, id uuid.UUID, req models.UpdateUser) error {
+	u := f.users[id]
+	u.Username, u.Email, u.FullName, u.IsActive = req.Username, req.Email, req.FullName, req.IsActive
+	f.users[id] = u
+	return nil
+}
+
+func TestUpdateUserKeepsLastAdmin(t *testing.T) {
+	admin, other, adminRole := uuid.New(), uuid.New(), uuid.New()
+
+	users := &fakeUpdateUserRepo{users: map[uuid.UUID]models.User{
+		admin: {ID: admin, Username: "admin", Email: "admin@unair.ac.id", FullName: "Admin", RoleID: adminRole, IsActive: true},
+		other: {ID: other, Username: "admin2", Email: "admin2@unair.ac.id", FullName: "Admin Dua", RoleID: adminRole, IsActive: true},
+	}}
+	roles := &fakeRoleRepo{
+		permissions: []models.Permission{{Name: "users:read"}, {Name: "users:update"}},
+		admins:      []string{admin.String()},
+	}
+	sessions := &fakeSessionRepo{}
+	app, token := newFakeAppWithRepos(t, container.Repositories{
+		User:    users,
+		Role:    roles,
+		Session: sessions,
+		Audit:   &fakeAuditRepo{},
+		Policy:  &fakePolicyRepo{granted: map[string]string{"users:update": models.ScopeAll}},
+	})
+
+	do := func(id uuid.UUID, body string) (int, string) {
+		req := httptest.NewRequest("PUT", "/api/v1/users/"+id.String(), strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out struct {
+			Error struct{ Code string } `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out.Error.Code
+	}
+
+	// role_id diabaikan, role hanya dapat diganti lewat PUT /users/:id/role
+	body := func(active bool) string {
+		return fmt.Sprintf(`{"username":"admin","email":"admin@unair.ac.id","full_name":"Admin","role_id":%q,"is_active":%t}`, uuid.NewString(), active)
+	}
+
+	if status, code := do(admin, body(false)); status != 409 || code != apperror.CodeLastAdminPermission {
+		t.Errorf("menonaktifkan admin terakhir: status = %d, code = %s", status, code)
+	}
+	if !users.users[admin].IsActive {
+		t.Error("admin terakhir tidak boleh nonaktif")
+	}
+
+	if status, _ := do(admin, body(true)); status != 200 || users.users[admin].RoleID != adminRole {
+		t.Errorf("update biasa: status = %d, role = %s", status, users.users[admin].RoleID)
+	}
+
+	roles.admins = append(roles.admins, other.String())
+	if status, _ := do(admin, body(false)); status != 200 || users.users[admin].IsActive {
+		t.Errorf("masih ada admin lain: status = %d", status)
+	}
+	if len(sessions.revoked) != 1 || sessions.revoked[0] != admin.String() {
+		t.Errorf("sesi dicabut = %v, want sesi %s", sessions.revoked, admin)
+	}
+}
+
+type fakeImpersonationSessionRepo struct {
+	repository.SessionRepository
+	sessions []models.Session
+}
+
+func (f *fakeImpersonationSessionRepo) CreateSession(ctx context.Context, session models.Session) error {
+	f.sessions = append(f.sessions, session)
+	return nil
+}
+
+func (f *fakeImpersonationSessionRepo) RevokeSessionsByUserID(ctx context.Context, userID string, revokedBy string) ([]string, error) {
+	var ids []string
+	for _, s := range f.sessions {
+		if s.UserID.String() == userID {
+			ids = append(ids, s.ID.String())
+		}
+	}
+	return ids, nil
+}
+
+func TestImpersonationTokenIsRevocable(t *testing.T) {
+	target := uuid.New()
+	users := &fakeUpdateUserRepo{users: map[uuid.UUID]models.User{
+		target: {ID: target, Username: "andi", RoleName: models.RoleMahasiswa, IsActive: true},
+	}}
+	sessions := &fakeImpersonationSessionRepo{}
+	app, adminToken := newFakeAppWithRepos(t, container.Repositories{
+		User:    users,
+		Role:    &fakeRoleRepo{},
+		Session: sessions,
+		Audit:   &fakeAuditRepo{},
+		Policy: &fakePolicyRepo{granted: map[string]string{
+			"users:impersonate": models.ScopeAll,
+			"users:update":      models.ScopeAll,
+		}},
+	})
+
+	do := func(method, path, token string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"username":"andi","email":"andi@unair.ac.id","full_name":"Andi"}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+	errorCode := func(out map[string]interface{}) string {
+		e, _ := out["error"].(map[string]interface{})
+		code, _ := e["code"].(string)
+		return code
+	}
+
+	status, out := do("POST", "/api/v1/users/"+target.String()+"/impersonate", adminToken)
+	data, _ := out["data"].(map[string]interface{})
+	token, _ := data["token"].(string)
+	if status != 200 || token == "" || len(sessions.sessions) != 1 || sessions.sessions[0].UserID != target {
+		t.Fatalf("impersonate: status = %d, sessions = %+v", status, sessions.sessions)
+	}
+
+	path := "/api/v1/users/" + target.String()
+	if status, out := do("PUT", path, token); status != 403 || errorCode(out) != apperror.CodeImpersonationForbidden {
+		t.Errorf("PUT /users/:id selama impersonasi: status = %d, code = %s", status, errorCode(out))
+	}
+
+	if status, _ := do("POST", path+"/logout", adminToken); status != 200 {
+		t.Fatalf("force logout: status = %d", status)
+	}
+	if status, out := do("PUT", path, token); status != 401 || errorCode(out) != apperror.CodeSessionRevoked {
+		t.Errorf("token impersonasi setelah force logout: status = %d, code = %s", status, errorCode(out))
+	}
+}
+
+// Token impersonasi hidup lebih lama dari AccessTokenTTL, sehingga entry denylist-nya tidak boleh
+// kedaluwarsa bersama umur access token biasa
+func TestRevokedImpersonationTokenStaysDeniedAfterAccessTTL(t *testing.T) {
+	target := uuid.New()
+	sessions := &fakeImpersonationSessionRepo{}
+	app, adminToken := newFakeAppWithRepos(t, container.Repositories{
+		User: &fakeUpdateUserRepo{users: map[uuid.UUID]models.User{
+			target: {ID: target, Username: "andi", RoleName: models.RoleMahasiswa, IsActive: true},
+		}},
+		Role:    &fakeRoleRepo{},
+		Session: sessions,
+		Audit:   &fakeAuditRepo{},
+		Policy: &fakePolicyRepo{granted: map[string]string{
+			"users:impersonate": models.ScopeAll,
+			"users:update":      models.ScopeAll,
+		}},
+	})
+	accessTTL := utils.AccessTokenTTL
+	utils.AccessTokenTTL = 50 * time.Millisecond
+	t.Cleanup(func() { utils.AccessTokenTTL = accessTTL })
+
+	do := func(method, path, token, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out struct {
+			Data struct {
+				Token string `json:"token"`
+			} `json:"data"`
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		if out.Data.Token != "" {
+			return resp.StatusCode, out.Data.Token
+		}
+		return resp.StatusCode, out.Error.Code
+	}
+
+	path := "/api/v1/users/" + target.String()
+	status, token := do("POST", path+"/impersonate", adminToken, `{"duration_minutes":60}`)
+	if status != 200 {
+		t.Fatalf("impersonate: status = %d", status)
+	}
+	if status, _ := do("POST", path+"/logout", adminToken, ""); status != 200 {
+		t.Fatalf("force logout: status = %d", status)
+	}
+
+	// Waktu berjalan melewati AccessTokenTTL, token impersonasi sendiri masih berlaku 60 menit
+	time.Sleep(2 * utils.AccessTokenTTL)
+	if status, code := do("GET", "/api/v1/auth/profile", token, ""); status != 401 || code != apperror.CodeSessionRevoked {
+		t.Errorf("token impersonasi setelah AccessTokenTTL lewat: status = %d, code = %s, want 401 %s", status, code, apperror.CodeSessionRevoked)
+	}
+}
+
+func TestImpersonationRefusesPrivilegedCustomRole(t *testing.T) {
+	target := uuid.New()
+	roles := &fakeRoleRepo{permissions: []models.Permission{{Name: "achievements:read"}, {Name: "roles:update"}}}
+	sessions := &fakeImpersonationSessionRepo{}
+	app, adminToken := newFakeAppWithRepos(t, container.Repositories{
+		User: &fakeUpdateUserRepo{users: map[uuid.UUID]models.User{
+			target: {ID: target, Username: "operator", RoleID: uuid.New(), RoleName: "Operator", IsActive: true},
+		}},
+		Role:    roles,
+		Session: sessions,
+		Audit:   &fakeAuditRepo{},
+		Policy:  &fakePolicyRepo{granted: map[string]string{"users:impersonate": models.ScopeAll}},
+	})
+
+	impersonate := func() (int, string) {
+		req := httptest.NewRequest("POST", "/api/v1/users/"+target.String()+"/impersonate", nil)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		var out struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out.Error.Code
+	}
+
+	if status, code := impersonate(); status != 403 || code != apperror.CodeImpersonateAdmin || len(sessions.sessions) != 0 {
+		t.Errorf("role kustom dengan roles:update: status = %d, code = %s, sessions = %d", status, code, len(sessions.sessions))
+	}
+
+	roles.permissions = []models.Permission{{Name: "achievements:read"}}
+	if status, _ := impersonate(); status != 200 {
+		t.Errorf("role tanpa permission administratif: status = %d, want 200", status)
+	}
+}
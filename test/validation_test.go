@@ -0,0 +1,76 @@
+package test
+
+import (
+	"testing"
+	"uas/app/apperror"
+	"uas/app/models"
+	"uas/helpers"
+)
+
+func TestValidateCreateUserRequest(t *testing.T) {
+	valid := models.CreateUserRequest{
+		Username: "andi_mhs",
+		Email:    "andi@student.unair.ac.id",
+		Password: "password123",
+		FullName: "Andi Pratama",
+		RoleID:   "7f1c1e8e-2f55-4a51-9d5c-0f3f6f1f2a11",
+		RoleName: models.RoleMahasiswa,
+		Student:  &models.Student{StudentID: "434221001", ProgramStudy: "Teknik Informatika", AcademicYear: "2022"},
+	}
+	if err := helpers.Validate(&valid); err != nil {
+		t.Fatalf("request valid ditolak: %v", err)
+	}
+
+	invalid := valid
+	invalid.Username = "andi mhs!"
+	invalid.Email = "bukan-email"
+	invalid.Password = "pendek"
+	invalid.RoleID = "123"
+	invalid.Student = &models.Student{StudentID: "43A", ProgramStudy: "Teknik Informatika"}
+
+	err := helpers.Validate(&invalid)
+	appErr, ok := apperror.As(err)
+	if !ok || appErr.Kind != apperror.KindValidation {
+		t.Fatalf("err = %v, want error validasi", err)
+	}
+
+	want := map[string]string{
+		"username":           "username",
+		"email":              "email",
+		"password":           "password",
+		"role_id":            "uuid",
+		"student.student_id": "nim",
+	}
+	got := map[string]string{}
+	for _, f := range appErr.Fields {
+		got[f.Field] = f.Code
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("field %s: code = %q, want %q (semua: %v)", field, got[field], code, got)
+		}
+	}
+}
+
+func TestValidateEnumsAndBlank(t *testing.T) {
+	cases := []struct {
+		name  string
+		req   interface{}
+		field string
+	}{
+		{"jenis prestasi", &models.CreateAchievementRequest{AchievementType: "lomba", Title: "Juara 1"}, "achievementType"},
+		{"judul kosong", &models.CreateAchievementRequest{AchievementType: "competition", Title: "   "}, "title"},
+		{"scope", &models.GrantPermissionRequest{PermissionID: "7f1c1e8e-2f55-4a51-9d5c-0f3f6f1f2a11", Scope: "global"}, "scope"},
+		{"advisor", &models.UpdateAdvisorRequest{AdvisorID: "dosen-1"}, "advisor_id"},
+		{"catatan penolakan", &models.RejectAchievementRequest{}, "rejection_note"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			appErr, ok := apperror.As(helpers.Validate(tc.req))
+			if !ok || len(appErr.Fields) != 1 || appErr.Fields[0].Field != tc.field {
+				t.Errorf("fields = %+v, want satu error pada %s", appErr, tc.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+	"uas/app/models"
+)
+
+// CanonicalJSON menormalkan JSON (urutan key & spasi) agar hash konsisten
+// antara data yang ditulis dan data yang dibaca ulang dari kolom JSONB
+func CanonicalJSON(raw []byte) []byte {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return raw
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+// HashAuditPII menghitung hash kolom data pribadi entry audit log, yang disimpan sebagai PIIHash
+// agar entry tetap dapat di-hash ulang setelah kolom tersebut diredaksi
+func HashAuditPII(entry models.AuditLog) string {
+	return hashFields([]string{
+		entry.ActorUsername,
+		string(CanonicalJSON(entry.Before)),
+		string(CanonicalJSON(entry.After)),
+		entry.IP,
+		entry.UserAgent,
+		entry.ImpersonatorUsername,
+	})
+}
+
+// HashAuditEntry menghitung hash entry audit log yang dirantai dengan PrevHash
+func HashAuditEntry(entry models.AuditLog) string {
+	// Entry dengan PIIHash hanya meng-hash kolom non-pribadi ditambah PIIHash
+	if entry.PIIHash != "" {
+		return hashFields([]string{
+			entry.PrevHash,
+			entry.ActorID,
+			entry.Action,
+			entry.TargetType,
+			entry.TargetID,
+			entry.RequestID,
+			entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+			entry.ImpersonatorID,
+			entry.PIIHash,
+		})
+	}
+
+	fields := []string{
+		entry.PrevHash,
+		entry.ActorID,
+		entry.ActorUsername,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		string(CanonicalJSON(entry.Before)),
+		string(CanonicalJSON(entry.After)),
+		entry.IP,
+		entry.UserAgent,
+		entry.RequestID,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	// Field impersonator hanya ikut di-hash jika ada, agar entry lama tetap valid
+	if entry.ImpersonatorID != "" {
+		fields = append(fields, entry.ImpersonatorID, entry.ImpersonatorUsername)
+	}
+
+	return hashFields(fields)
+}
+
+func hashFields(fields []string) string {
+	var buf bytes.Buffer
+	// Prefix panjang tiap field agar batas antar field tidak ambigu
+	for _, f := range fields {
+		buf.WriteString(strconv.Itoa(len(f)))
+		buf.WriteByte(':')
+		buf.WriteString(f)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Denylist sesi yang dicabut. Disimpan selama umur access token terpanjang (termasuk token
+// impersonasi), setelah itu seluruh token sesi sudah expired dan refresh token ditolak lewat database.
+var sessionDenylist = struct {
+	sync.Mutex
+	entries map[string]time.Time
+}{entries: map[string]time.Time{}}
+
+func DenySession(sessionID string) {
+	sessionDenylist.Lock()
+	defer sessionDenylist.Unlock()
+
+	sessionDenylist.entries[sessionID] = time.Now().Add(MaxAccessTokenLifetime())
+}
+
+// PruneDeniedSessions membuang entri yang sudah melewati umur access token terpanjang.
+// Dipanggil berkala oleh background worker.
+func PruneDeniedSessions() {
+	sessionDenylist.Lock()
+	defer sessionDenylist.Unlock()
+
+	now := time.Now()
+	for id, expiresAt := range sessionDenylist.entries {
+		if now.After(expiresAt) {
+			delete(sessionDenylist.entries, id)
+		}
+	}
+}
+
+func IsSessionDenied(sessionID string) bool {
+	sessionDenylist.Lock()
+	defer sessionDenylist.Unlock()
+
+	expiresAt, ok := sessionDenylist.entries[sessionID]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(expiresAt) {
+		delete(sessionDenylist.entries, sessionID)
+		return false
+	}
+	return true
+}
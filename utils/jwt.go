@@ -1,22 +1,45 @@
 package utils
 
 import (
-	"os"
 	"time"
 	"uas/app/models"
+	"uas/config"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var JwtSecret = []byte(os.Getenv("JWT_SECRET"))
+// Diisi oleh ConfigureJWT saat startup dari config.JWTConfig
+var (
+	JwtSecret       []byte
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// ImpersonationTokenMaxTTL adalah umur maksimal token impersonasi (batas max pada ImpersonateRequest)
+const ImpersonationTokenMaxTTL = 60 * time.Minute
+
+// MaxAccessTokenLifetime adalah umur terpanjang access token yang dapat diterbitkan,
+// baik token login biasa maupun token impersonasi
+func MaxAccessTokenLifetime() time.Duration {
+	return max(AccessTokenTTL, ImpersonationTokenMaxTTL)
+}
 
-func GenerateToken(user models.User) (string, error) {
+// ConfigureJWT mengatur secret dan umur token dari konfigurasi aplikasi
+func ConfigureJWT(cfg config.JWTConfig) {
+	JwtSecret = []byte(cfg.Secret)
+	AccessTokenTTL = cfg.AccessTTL
+	RefreshTokenTTL = cfg.RefreshTTL
+}
+
+func GenerateToken(user models.User, sessionID string) (string, error) {
 	claims := models.JWTClaims{
 		UserID: user.ID,
 		Username: user.Username,
 		RoleName: user.RoleName,
+		SessionID: sessionID,
+		Language: user.PreferredLanguage,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -25,11 +48,34 @@ func GenerateToken(user models.User) (string, error) {
 	return token.SignedString(JwtSecret)
 }
 
-func GenerateRefreshToken(user models.User) (string, error) {
+// GenerateImpersonationToken membuat access token untuk user target dengan klaim "act" berisi admin asli.
+// Token ini tidak memiliki refresh token sehingga sesi berakhir saat token expired, dan terikat ke
+// sessionID agar dapat dicabut lewat denylist seperti token login biasa.
+func GenerateImpersonationToken(target models.User, actor models.ActorClaim, sessionID string, duration time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(duration)
+	claims := models.JWTClaims{
+		UserID:    target.ID,
+		Username:  target.Username,
+		RoleName:  target.RoleName,
+		SessionID: sessionID,
+		Actor:     &actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(JwtSecret)
+	return signed, expiresAt, err
+}
+
+func GenerateRefreshToken(user models.User, sessionID string) (string, error) {
     claims := jwt.MapClaims{
         "userId": user.ID,
         "role":   user.RoleName,
-        "exp":    time.Now().Add(7 * 24 * time.Hour).Unix(),
+        "sid":    sessionID,
+        "exp":    time.Now().Add(RefreshTokenTTL).Unix(),
         "type":   "refresh",
     }
 
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"uas/config"
+)
+
+type loggerKey struct{}
+
+// NewLogger membuat logger terstruktur sesuai LOG_LEVEL dan LOG_FORMAT
+func NewLogger(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// WithLogger menyimpan logger (biasanya sudah berisi request_id) ke dalam context
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger mengambil logger dari context, atau logger default jika tidak ada
+func Logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -1,6 +1,11 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"math/big"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password),
@@ -11,4 +16,29 @@ func HashPassword(password string) (string, error) {
 func CheckPassword(password, hash string) bool { 
     err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) 
     return err == nil 
-}
\ No newline at end of file
+}
+
+const passwordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GeneratePassword membuat password acak sepanjang length yang selalu berisi huruf dan angka
+func GeneratePassword(length int) (string, error) {
+	for {
+		buf := make([]byte, length)
+		hasLetter, hasDigit := false, false
+		for i := range buf {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(passwordAlphabet))))
+			if err != nil {
+				return "", err
+			}
+			buf[i] = passwordAlphabet[n.Int64()]
+			if buf[i] >= '0' && buf[i] <= '9' {
+				hasDigit = true
+			} else {
+				hasLetter = true
+			}
+		}
+		if hasLetter && hasDigit {
+			return string(buf), nil
+		}
+	}
+}